@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yarlson/tap"
+	"refurbed.com/hackathon/reporting"
+)
+
+var anomalyStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("1")).
+	Bold(true)
+
+var anomalyBlockStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("1")).
+	Background(lipgloss.Color("1"))
+
+// revenueAnomalies returns the days between start and end with anomalous
+// revenue, keyed by day.
+func revenueAnomalies(dataset *reporting.OrderDataset, start, end time.Time) map[time.Time]reporting.Anomaly {
+	res := map[time.Time]reporting.Anomaly{}
+	for _, a := range dataset.DailyAnomalies(start, end, reporting.DefaultAnomalyConfig()) {
+		if a.KPI == reporting.KPIRevenue {
+			res[a.Day] = a
+		}
+	}
+	return res
+}
+
+func renderAnomalies(dataset *reporting.OrderDataset) {
+	fmt.Println("Anomalies in daily KPIs over the last 8 weeks")
+	fmt.Println()
+
+	_, latest := dataset.DateRange()
+	latest = time.Date(latest.Year(), latest.Month(), latest.Day(), 0, 0, 0, 0, time.UTC)
+
+	anomalies := dataset.DailyAnomalies(latest.AddDate(0, 0, -7*8), latest.AddDate(0, 0, -1), reporting.DefaultAnomalyConfig())
+	if len(anomalies) == 0 {
+		fmt.Println("No anomalies found")
+		return
+	}
+
+	renderAnomaliesTable(anomalies)
+}
+
+func renderAnomaliesTable(data []reporting.Anomaly) {
+	textData := make([][]string, 0)
+	for _, a := range data {
+		textData = append(textData, []string{
+			a.Day.Format("2006-01-02 Mon"),
+			a.KPI.String(),
+			formatKPIValue(a.KPI, a.Value),
+			formatKPIValue(a.KPI, a.Expected),
+			anomalyStyle.Render(fmt.Sprintf("%+.1f", a.Score)),
+		})
+	}
+
+	tap.Table(
+		[]string{"Day", "KPI", "Value", "Expected", "Score"},
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold, MaxWidth: 140})
+}
+
+func formatKPIValue(kpi reporting.KPI, v float64) string {
+	switch kpi {
+	case reporting.KPIReturnRate:
+		return fmt.Sprintf("%.2f%%", 100*v)
+	case reporting.KPIOrderCount:
+		return fmt.Sprintf("%.0f", v)
+	default:
+		return fmt.Sprintf("%.2f", v)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/yarlson/tap"
+	"refurbed.com/hackathon/reporting"
+)
+
+const maxAssociationRules = 20
+
+func renderFrequentlyBoughtTogether(dataset *reporting.OrderDataset) {
+	fmt.Println("Frequently bought together")
+	fmt.Println()
+
+	minSupportText := tap.Text(context.Background(), tap.TextOptions{
+		Message:      "Minimum support (share of orders):",
+		DefaultValue: "0.001",
+		Placeholder:  "0.001",
+		Validate: func(s string) error {
+			if s == "" {
+				return nil
+			}
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil || v < 0 || v > 1 {
+				return fmt.Errorf("enter a number between 0 and 1")
+			}
+			return nil
+		},
+	})
+	minSupport, err := strconv.ParseFloat(minSupportText, 64)
+	if err != nil {
+		minSupport = 0.001
+	}
+
+	fmt.Println("Categories")
+	renderAssociationRulesTable(dataset.CategoryAssociationRules(minSupport))
+	fmt.Println("Items")
+	renderAssociationRulesTable(dataset.ItemAssociationRules(minSupport))
+}
+
+func renderAssociationRulesTable(rules []reporting.AssociationRule) {
+	if len(rules) > maxAssociationRules {
+		rules = rules[:maxAssociationRules]
+	}
+
+	textData := make([][]string, 0)
+	for _, r := range rules {
+		textData = append(textData, []string{
+			r.Antecedent,
+			r.Consequent,
+			fmt.Sprintf("%d", r.Orders),
+			fmt.Sprintf("%.3f%%", 100*r.Support),
+			fmt.Sprintf("%.2f%%", 100*r.Confidence),
+			fmt.Sprintf("%.2f", r.Lift),
+		})
+	}
+
+	tap.Table(
+		[]string{"Bought", "Also bought", "Orders", "Support", "Confidence", "Lift"},
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold, MaxWidth: 140})
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yarlson/tap"
+	"refurbed.com/hackathon/reporting"
+)
+
+const maxCohorts = 12
+
+var heatStyles = []lipgloss.Style{
+	lipgloss.NewStyle().Foreground(lipgloss.Color("15")).Background(lipgloss.Color("236")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("15")).Background(lipgloss.Color("22")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("15")).Background(lipgloss.Color("28")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("0")).Background(lipgloss.Color("34")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("0")).Background(lipgloss.Color("40")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("0")).Background(lipgloss.Color("46")),
+}
+
+// heatCell colours text by where share falls between 0 and maxShare.
+func heatCell(text string, share, maxShare float64) string {
+	if maxShare <= 0 {
+		return heatStyles[0].Render(text)
+	}
+	idx := int(share / maxShare * float64(len(heatStyles)-1))
+	idx = min(max(idx, 0), len(heatStyles)-1)
+	return heatStyles[idx].Render(text)
+}
+
+func renderCohorts(dataset *reporting.OrderDataset) {
+	fmt.Println("Monthly cohort retention")
+	fmt.Println()
+
+	cohorts := dataset.MonthlyCohorts()
+	if len(cohorts) > maxCohorts {
+		cohorts = cohorts[len(cohorts)-maxCohorts:]
+	}
+
+	renderCohortRetentionTable(cohorts)
+	renderCohortRevenueTable(cohorts, dataset.ReportingCurrency())
+}
+
+func cohortHeaders(first string, cohorts []reporting.Cohort) []string {
+	headers := []string{first, "Customers"}
+	if len(cohorts) == 0 {
+		return headers
+	}
+	for offset := range cohorts[0].Active {
+		headers = append(headers, fmt.Sprintf("M+%d", offset))
+	}
+	return headers
+}
+
+func renderCohortRetentionTable(cohorts []reporting.Cohort) {
+	maxRetention := 0.0
+	for _, c := range cohorts {
+		for offset := 1; offset < len(c.Active); offset++ {
+			maxRetention = max(maxRetention, c.Retention(offset))
+		}
+	}
+
+	textData := make([][]string, 0)
+	for _, c := range cohorts {
+		row := []string{c.Month.Format("2006-01"), fmt.Sprintf("%d", c.Customers)}
+		for offset := range c.Active {
+			text := fmt.Sprintf("%5.1f%%", 100*c.Retention(offset))
+			if offset == 0 {
+				row = append(row, text)
+				continue
+			}
+			row = append(row, heatCell(text, c.Retention(offset), maxRetention))
+		}
+		textData = append(textData, row)
+	}
+
+	tap.Table(
+		cohortHeaders("Cohort", cohorts),
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold, MaxWidth: 200})
+}
+
+func renderCohortRevenueTable(cohorts []reporting.Cohort, currency reporting.Currency) {
+	textData := make([][]string, 0)
+	for _, c := range cohorts {
+		row := []string{c.Month.Format("2006-01"), fmt.Sprintf("%d", c.Customers)}
+		for _, revenue := range c.Revenue {
+			row = append(row, fmt.Sprintf("%s %.0f", currency.Symbol(), revenue.InexactFloat64()))
+		}
+		textData = append(textData, row)
+	}
+
+	tap.Table(
+		cohortHeaders("Cohort revenue", cohorts),
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold, MaxWidth: 200})
+}
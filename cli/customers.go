@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/NimbleMarkets/ntcharts/barchart"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yarlson/tap"
+	"refurbed.com/hackathon/reporting"
+)
+
+var returningBlockStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("4")).
+	Background(lipgloss.Color("4"))
+
+func renderCustomers(dataset *reporting.OrderDataset, comparisons []reporting.Comparison) {
+	fmt.Println("Customers")
+	fmt.Println()
+
+	currency := dataset.ReportingCurrency()
+	fmt.Printf("Distinct customers: %d\n", dataset.NumCustomers())
+	fmt.Printf("Repeat purchase rate: %s\n", formatRateEstimate(dataset.RepeatPurchaseRateEstimate()))
+	fmt.Printf("Average customer lifetime value: %s\n", formatMoney(currency, dataset.AverageCustomerLifetimeValue().InexactFloat64()))
+	fmt.Println()
+
+	_, latest := dataset.DateRange()
+	latest = time.Date(latest.Year(), latest.Month(), latest.Day(), 0, 0, 0, 0, time.UTC)
+	byWeek := dataset.CustomersByWeek(latest.AddDate(0, 0, -(7*7)-1), latest.AddDate(0, 0, -1), comparisons...)
+
+	renderCustomersByWeekTable(byWeek, comparisons)
+	renderCustomersByWeekGraph(byWeek)
+	renderOrdersPerCustomerTable(dataset.OrdersPerCustomerDistribution())
+}
+
+func renderCustomersByWeekTable(data []reporting.PeriodCustomers, comparisons []reporting.Comparison) {
+	textData := make([][]string, 0)
+	for _, p := range data {
+		row := []string{
+			p.Title,
+			fmt.Sprintf("%d", p.New),
+			fmt.Sprintf("%d", p.Returning),
+			fmt.Sprintf("%d", p.Total()),
+		}
+		textData = append(textData, append(row, comparisonCells(p.Compared, formatCount, formatSignedCount)...))
+	}
+
+	tap.Table(
+		append([]string{"Week", "New", "Returning", "Total"}, comparisonHeaders(comparisons)...),
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold})
+}
+
+func renderCustomersByWeekGraph(data []reporting.PeriodCustomers) {
+	values := make([]barchart.BarData, 0)
+	for _, p := range data {
+		values = append(
+			values,
+			barchart.BarData{
+				Label: p.Start.Format("01-02"),
+				Values: []barchart.BarValue{
+					{Name: "New", Value: float64(p.New), Style: blockStyle},
+					{Name: "Returning", Value: float64(p.Returning), Style: returningBlockStyle},
+				}})
+		values = append(values, comparisonBars(p.Compared)...)
+	}
+
+	bc := barchart.New(140, 15)
+	bc.SetShowAxis(true)
+	bc.PushAll(values)
+	bc.Draw()
+
+	fmt.Println(bc.View())
+}
+
+func renderOrdersPerCustomerTable(data []reporting.OrdersPerCustomer) {
+	const maxBucket = 5
+
+	buckets := make([]int, maxBucket+1)
+	total := 0
+	for _, d := range data {
+		buckets[min(d.NumOrders, maxBucket)] += d.NumCustomers
+		total += d.NumCustomers
+	}
+
+	textData := make([][]string, 0)
+	for numOrders := 1; numOrders <= maxBucket; numOrders++ {
+		label := fmt.Sprintf("%d", numOrders)
+		if numOrders == maxBucket {
+			label += "+"
+		}
+		share := 0.0
+		if total > 0 {
+			share = 100 * float64(buckets[numOrders]) / float64(total)
+		}
+		textData = append(textData, []string{label, fmt.Sprintf("%d", buckets[numOrders]), fmt.Sprintf("%.2f%%", share)})
+	}
+
+	tap.Table(
+		[]string{"Orders", "Customers", "Share"},
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold})
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/NimbleMarkets/ntcharts/barchart"
+	"github.com/yarlson/tap"
+	"refurbed.com/hackathon/reporting"
+)
+
+func renderMarketplaceEarnings(dataset *reporting.OrderDataset, registry *reporting.MetricRegistry, comparisons []reporting.Comparison) {
+	fmt.Println("Marketplace earnings")
+	fmt.Println()
+
+	currency := dataset.ReportingCurrency()
+	fmt.Printf("GMV: %s\n", evaluateMetric(dataset, registry, "gmv"))
+	fmt.Printf("Commission: %s, take rate %s\n", evaluateMetric(dataset, registry, "commission"), evaluateMetric(dataset, registry, "take_rate"))
+	fmt.Printf("Commission net of refunds: %s, take rate %s\n", evaluateMetric(dataset, registry, "net_commission"), evaluateMetric(dataset, registry, "net_take_rate"))
+	fmt.Println()
+
+	_, latest := dataset.DateRange()
+	latest = time.Date(latest.Year(), latest.Month(), latest.Day(), 0, 0, 0, 0, time.UTC)
+	byWeek := dataset.EarningsByWeek(latest.AddDate(0, 0, -(7*7)-1), latest.AddDate(0, 0, -1), comparisons...)
+
+	weeklyTakeRate := metricColumn(dataset, registry, "take_rate", reporting.GroupByWeek)
+	renderEarningsTable("Week", byWeek, func(e reporting.Earnings) string {
+		return weeklyTakeRate(e.Start.Format(time.DateOnly))
+	}, comparisons, currency)
+	renderEarningsGraph(byWeek)
+	categoryTakeRate := metricColumn(dataset, registry, "take_rate", reporting.GroupByCategory)
+	renderEarningsTable("Category", dataset.EarningsByCategory(), func(e reporting.Earnings) string {
+		return categoryTakeRate(e.Title)
+	}, nil, currency)
+	countryTakeRate := metricColumn(dataset, registry, "take_rate", reporting.GroupByCountry)
+	renderEarningsTable("Country", dataset.EarningsByCountry(), func(e reporting.Earnings) string {
+		return countryTakeRate(e.Title)
+	}, nil, currency)
+}
+
+// renderEarningsTable compares the net commission of every row with the
+// given comparisons. takeRate formats the take rate metric of a row.
+func renderEarningsTable(title string, data []reporting.Earnings, takeRate func(e reporting.Earnings) string, comparisons []reporting.Comparison, currency reporting.Currency) {
+	money := func(v float64) string { return formatMoney(currency, v) }
+	textData := make([][]string, 0)
+	for _, e := range data {
+		row := []string{
+			e.Title,
+			formatMoney(currency, e.GMV.InexactFloat64()),
+			formatMoney(currency, e.Commission.InexactFloat64()),
+			formatMoney(currency, e.NetCommission.InexactFloat64()),
+			takeRate(e),
+		}
+		textData = append(textData, append(row, comparisonCells(e.Compared, money, formatSignedAmount)...))
+	}
+
+	tap.Table(
+		append([]string{title, "GMV", "Commission", "Net commission", "Take rate"}, comparisonHeaders(comparisons)...),
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold, MaxWidth: 140})
+}
+
+func renderEarningsGraph(data []reporting.Earnings) {
+	values := make([]barchart.BarData, 0)
+	for _, e := range data {
+		values = append(
+			values,
+			barchart.BarData{
+				Label:  e.Start.Format("01-02"),
+				Values: []barchart.BarValue{{Name: "Net commission", Value: e.NetCommission.InexactFloat64(), Style: blockStyle}}})
+		values = append(values, comparisonBars(e.Compared)...)
+	}
+
+	bc := barchart.New(140, 15)
+	bc.SetShowAxis(true)
+	bc.PushAll(values)
+	bc.Draw()
+
+	fmt.Println(bc.View())
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+
+	"github.com/yarlson/tap"
+	"refurbed.com/hackathon/reporting"
+)
+
+func selectItemFilter(dataset *reporting.OrderDataset) reporting.ItemFilter {
+	countries := []tap.SelectOption[string]{{Value: "", Label: "All countries"}}
+	for _, c := range dataset.AllCountries() {
+		countries = append(countries, tap.SelectOption[string]{Value: c, Label: c})
+	}
+	country := tap.Select(context.Background(), tap.SelectOptions[string]{
+		Message: "Filter by country:",
+		Options: countries,
+	})
+
+	categories := []tap.SelectOption[reporting.Category]{{Value: "", Label: "All categories"}}
+	for _, c := range dataset.AllCategories() {
+		categories = append(categories, tap.SelectOption[reporting.Category]{Value: c, Label: string(c)})
+	}
+	category := tap.Select(context.Background(), tap.SelectOptions[reporting.Category]{
+		Message: "Filter by category:",
+		Options: categories,
+	})
+
+	return reporting.ItemFilter{Country: country, Category: category}
+}
+
+func describeItemFilter(filter reporting.ItemFilter) string {
+	country, category := "all countries", "all categories"
+	if filter.Country != "" {
+		country = filter.Country
+	}
+	if filter.Category != "" {
+		category = string(filter.Category)
+	}
+	return country + ", " + category
+}
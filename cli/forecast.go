@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/NimbleMarkets/ntcharts/barchart"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yarlson/tap"
+	"refurbed.com/hackathon/reporting"
+)
+
+var forecastBlockStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("8")).
+	Background(lipgloss.Color("8"))
+
+func renderRevenueForecast(dataset *reporting.OrderDataset, basis reporting.RevenueBasis) {
+	granularity := tap.Select(context.Background(), tap.SelectOptions[string]{
+		Message: "Forecast granularity:",
+		Options: []tap.SelectOption[string]{
+			{Value: "Day", Label: "Daily", Hint: "weekly seasonality"},
+			{Value: "Week", Label: "Weekly"},
+		},
+	})
+	horizonText := tap.Text(context.Background(), tap.TextOptions{
+		Message:      "Number of periods to forecast:",
+		DefaultValue: "7",
+		Placeholder:  "7",
+		Validate: func(s string) error {
+			if s == "" {
+				return nil
+			}
+			if v, err := strconv.Atoi(s); err != nil || v <= 0 || v > 60 {
+				return fmt.Errorf("enter a number between 1 and 60")
+			}
+			return nil
+		},
+	})
+	horizon, err := strconv.Atoi(horizonText)
+	if err != nil {
+		horizon = 7
+	}
+
+	clearScreen()
+	fmt.Printf("Revenue forecast by %s (%s)\n", granularity, basis)
+	fmt.Println()
+
+	earliest, latest := dataset.DateRange()
+	earliest = time.Date(earliest.Year(), earliest.Month(), earliest.Day(), 0, 0, 0, 0, time.UTC)
+	latest = time.Date(latest.Year(), latest.Month(), latest.Day(), 0, 0, 0, 0, time.UTC)
+
+	var (
+		history  []reporting.IntervalRevenue
+		forecast []reporting.ForecastPoint
+		shown    int
+	)
+	switch granularity {
+	case "Week":
+		// Start on the first full week so a partial week does not skew the trend.
+		start := earliest.AddDate(0, 0, 7)
+		history = dataset.RevenueByWeekFor(basis, start, latest.AddDate(0, 0, -7))
+		forecast, err = dataset.ForecastRevenueByWeek(basis, start, latest.AddDate(0, 0, -7), horizon)
+		shown = 12
+	default:
+		history = dataset.RevenueByDayFor(basis, earliest, latest.AddDate(0, 0, -1))
+		forecast, err = dataset.ForecastRevenueByDay(basis, earliest, latest.AddDate(0, 0, -1), horizon)
+		shown = 28
+	}
+	if err != nil {
+		fmt.Printf("Forecasting failed: %v\n", err)
+		return
+	}
+	if len(history) > shown {
+		history = history[len(history)-shown:]
+	}
+
+	renderForecastTable(forecast, dataset.ReportingCurrency())
+	renderForecastGraph(history, forecast)
+}
+
+func renderForecastTable(data []reporting.ForecastPoint, currency reporting.Currency) {
+	textData := make([][]string, 0)
+	for _, f := range data {
+		textData = append(textData, []string{
+			f.Title,
+			formatMoney(currency, f.Forecast.InexactFloat64()),
+			formatMoney(currency, f.Lower.InexactFloat64()),
+			formatMoney(currency, f.Upper.InexactFloat64()),
+		})
+	}
+
+	tap.Table(
+		[]string{"Period", "Forecast", "Lower 95%", "Upper 95%"},
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold, MaxWidth: 140})
+}
+
+// renderForecastGraph draws the actual revenue followed by the forecast in a
+// shaded style.
+func renderForecastGraph(history []reporting.IntervalRevenue, forecast []reporting.ForecastPoint) {
+	values := make([]barchart.BarData, 0)
+	for _, r := range history {
+		values = append(
+			values,
+			barchart.BarData{
+				Label:  r.Start.Format("01-02"),
+				Values: []barchart.BarValue{{Name: "Revenue", Value: r.Revenue.InexactFloat64(), Style: blockStyle}}})
+	}
+	for _, f := range forecast {
+		values = append(
+			values,
+			barchart.BarData{
+				Label:  f.Start.Format("01-02"),
+				Values: []barchart.BarValue{{Name: "Forecast", Value: max(f.Forecast.InexactFloat64(), 0), Style: forecastBlockStyle}}})
+	}
+
+	bc := barchart.New(160, 15)
+	bc.SetShowAxis(true)
+	bc.PushAll(values)
+	bc.Draw()
+
+	fmt.Println(bc.View())
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yarlson/tap"
+	"refurbed.com/hackathon/reporting"
+)
+
+const flaggedCustomersLimit = 50
+
+func renderFraudReport(dataset *reporting.OrderDataset) {
+	fmt.Println("Fraud and abuse signals")
+	fmt.Println()
+
+	cfg := reporting.DefaultFraudConfig()
+	report := dataset.FraudReport(cfg)
+
+	tap.Message(fmt.Sprintf("%d of %d customers flagged with a score of at least %d", len(report.Customers), dataset.NumCustomers(), cfg.MinScore))
+
+	renderFlaggedCustomersTable(report.Customers)
+	renderUnusualDomainsTable(report.Domains)
+}
+
+func renderFlaggedCustomersTable(data []reporting.FlaggedCustomer) {
+	if len(data) > flaggedCustomersLimit {
+		data = data[:flaggedCustomersLimit]
+	}
+
+	textData := make([][]string, 0)
+	for _, c := range data {
+		reasons := make([]string, len(c.Signals))
+		for i, s := range c.Signals {
+			reasons[i] = fmt.Sprintf("%s: %s", s.Rule, s.Reason)
+		}
+		textData = append(textData, []string{
+			c.Email,
+			fmt.Sprintf("%d", c.Score),
+			fmt.Sprintf("%d", c.Orders),
+			fmt.Sprintf("%.2f%%", 100*c.RefundRatio),
+			strings.Join(reasons, "; "),
+		})
+	}
+
+	tap.Table(
+		[]string{"Customer", "Score", "Orders", "Refunded", "Reasons"},
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold, MaxWidth: 160})
+}
+
+func renderUnusualDomainsTable(data []reporting.DomainReturnRate) {
+	if len(data) == 0 {
+		return
+	}
+
+	textData := make([][]string, 0)
+	for _, d := range data {
+		textData = append(textData, []string{
+			d.Domain,
+			formatRateEstimate(d.Test.A),
+			formatRateEstimate(d.Test.B),
+			fmt.Sprintf("%.4f", d.Test.PValue),
+		})
+	}
+
+	tap.Table(
+		[]string{"Email domain", "Return rate", "Other domains", "p"},
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold, MaxWidth: 140})
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/NimbleMarkets/ntcharts/barchart"
+	"github.com/yarlson/tap"
+	"refurbed.com/hackathon/reporting"
+)
+
+func renderReturnRateByGrade(dataset *reporting.OrderDataset) {
+	fmt.Println("Return rate by condition grade (95% confidence intervals)")
+	fmt.Println()
+
+	tab := dataset.ReturnRateByGrade()
+	if len(tab.Grades) == 0 {
+		tap.Message("The dataset has no condition grades in its item specs")
+		return
+	}
+
+	headers := []string{"Category"}
+	for _, grade := range tab.Grades {
+		headers = append(headers, "Grade "+grade)
+	}
+
+	textData := make([][]string, 0)
+	for i, cat := range tab.Categories {
+		row := []string{string(cat)}
+		for _, e := range tab.Cells[i] {
+			row = append(row, greyIfUnreliable(formatRateEstimate(e), e))
+		}
+		textData = append(textData, row)
+	}
+	total := []string{"All"}
+	for _, e := range tab.ByGrade {
+		total = append(total, greyIfUnreliable(formatRateEstimate(e), e))
+	}
+	textData = append(textData, total)
+
+	tap.Table(
+		headers,
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold, MaxWidth: 140})
+	printUnreliableNote()
+
+	renderGradeGraph(tab)
+
+	segments := make([]string, 0)
+	estimates := map[string]reporting.RateEstimate{}
+	for i, grade := range tab.Grades {
+		segments = append(segments, "Grade "+grade)
+		estimates["Grade "+grade] = tab.ByGrade[i]
+	}
+	selectSegmentComparison(segments, estimates)
+}
+
+func renderGradeGraph(tab reporting.GradeCrossTab) {
+	values := make([]barchart.BarData, 0)
+	for i, grade := range tab.Grades {
+		values = append(
+			values,
+			barchart.BarData{
+				Label:  "Grade " + grade,
+				Values: []barchart.BarValue{{Name: "Return rate", Value: tab.ByGrade[i].Rate(), Style: blockStyle}}})
+	}
+
+	bc := barchart.New(
+		140, 15,
+		barchart.WithDataSet(values))
+
+	bc.Draw()
+
+	fmt.Println(bc.View())
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/yarlson/tap"
+	"refurbed.com/hackathon/reporting"
+)
+
+var heatmapTimezones = []string{"UTC", "Europe/Vienna", "Europe/Berlin", "Europe/Paris", "Europe/Warsaw", "Europe/Stockholm"}
+
+var weekdayLabels = []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+func renderWeekdayHourHeatmap(dataset *reporting.OrderDataset) {
+	timezones := make([]tap.SelectOption[string], 0)
+	for _, tz := range heatmapTimezones {
+		timezones = append(timezones, tap.SelectOption[string]{Value: tz, Label: tz})
+	}
+	tz := tap.Select(context.Background(), tap.SelectOptions[string]{
+		Message: "Timezone:",
+		Options: timezones,
+	})
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		loc = time.UTC
+	}
+	filter := selectItemFilter(dataset)
+	metric := tap.Select(context.Background(), tap.SelectOptions[string]{
+		Message: "Metric:",
+		Options: []tap.SelectOption[string]{
+			{Value: "Orders", Label: "Order count"},
+			{Value: "Revenue", Label: "Revenue"},
+		},
+	})
+
+	clearScreen()
+	fmt.Printf("%s by weekday and hour (%s, %s)\n", metric, loc, describeItemFilter(filter))
+	fmt.Println()
+
+	grid := dataset.WeekdayHourHeatmap(loc, filter)
+	var values [7][24]float64
+	for d := range 7 {
+		for h := range 24 {
+			if metric == "Revenue" {
+				values[d][h] = grid.Revenue[d][h].InexactFloat64()
+			} else {
+				values[d][h] = float64(grid.Orders[d][h])
+			}
+		}
+	}
+
+	fmt.Println(renderHeatmapGrid(values))
+}
+
+// renderHeatmapGrid draws one coloured cell per weekday and hour, scaled to
+// the busiest slot.
+func renderHeatmapGrid(values [7][24]float64) string {
+	maxValue := 0.0
+	for _, row := range values {
+		for _, v := range row {
+			maxValue = max(maxValue, v)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("     ")
+	for h := range 24 {
+		fmt.Fprintf(&b, "%3d", h)
+	}
+	b.WriteString("\n")
+	for d, row := range values {
+		fmt.Fprintf(&b, "%-5s", weekdayLabels[d])
+		for _, v := range row {
+			b.WriteString(heatCell("   ", v, maxValue))
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "\nScale: %s 0 %s %.0f\n", heatCell("   ", 0, 1), heatCell("   ", 1, 1), maxValue)
+	return b.String()
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/NimbleMarkets/ntcharts/barchart"
+	"github.com/yarlson/tap"
+	"refurbed.com/hackathon/reporting"
+)
+
+func renderOrderValueHistogram(dataset *reporting.OrderDataset, basis reporting.RevenueBasis) {
+	filter := selectItemFilter(dataset)
+	cfg := reporting.DefaultHistogramConfig()
+	cfg.LogScale = tap.Select(context.Background(), tap.SelectOptions[bool]{
+		Message: "Scale:",
+		Options: []tap.SelectOption[bool]{
+			{Value: false, Label: "Linear"},
+			{Value: true, Label: "Logarithmic", Hint: "bin width in decades"},
+		},
+	})
+	width := tap.Text(context.Background(), tap.TextOptions{
+		Message:     "Bin width (empty for automatic):",
+		Placeholder: "auto",
+		Validate: func(s string) error {
+			if strings.TrimSpace(s) == "" {
+				return nil
+			}
+			if w, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err != nil || w <= 0 {
+				return fmt.Errorf("enter a positive number")
+			}
+			return nil
+		},
+	})
+	cfg.BinWidth, _ = strconv.ParseFloat(strings.TrimSpace(width), 64)
+
+	clearScreen()
+	fmt.Printf("Order value distribution (%s, %s)\n", basis, describeItemFilter(filter))
+	fmt.Println()
+
+	currency := dataset.ReportingCurrency()
+	dist := dataset.OrderValueDistribution(basis, filter, cfg)
+	if dist.Orders == 0 {
+		tap.Message("No orders match the filter")
+		return
+	}
+	fmt.Printf("Orders: %d, median: %s\n", dist.Orders, formatMoney(currency, dist.Median))
+	fmt.Println()
+
+	headers := make([]string, 0)
+	row := make([]string, 0)
+	for _, p := range dist.Percentiles {
+		headers = append(headers, fmt.Sprintf("P%.0f", p.Percentile))
+		row = append(row, formatMoney(currency, p.Value))
+	}
+	tap.Table(
+		headers,
+		[][]string{row},
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold, MaxWidth: 140})
+
+	renderHistogramGraph(dist.Histogram)
+}
+
+func renderHistogramGraph(h reporting.Histogram) {
+	values := make([]barchart.BarData, 0)
+	for _, b := range h.Bins {
+		values = append(
+			values,
+			barchart.BarData{
+				Label:  fmt.Sprintf("%.0f", b.Lower),
+				Values: []barchart.BarValue{{Name: "Orders", Value: float64(b.Count), Style: blockStyle}}})
+	}
+
+	bc := barchart.New(
+		140, 15,
+		barchart.WithDataSet(values))
+
+	bc.Draw()
+
+	fmt.Println(bc.View())
+}
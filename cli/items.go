@@ -0,0 +1,134 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/NimbleMarkets/ntcharts/barchart"
+	"github.com/yarlson/tap"
+	"refurbed.com/hackathon/reporting"
+)
+
+const topItemsLimit = 20
+
+func selectWithSpecs() bool {
+	return tap.Confirm(context.Background(), tap.ConfirmOptions{
+		Message:  "Distinguish items by their specs?",
+		Active:   "Yes",
+		Inactive: "No",
+	})
+}
+
+func renderTopItems(dataset *reporting.OrderDataset, registry *reporting.MetricRegistry, basis reporting.RevenueBasis) {
+	options := make([]tap.SelectOption[reporting.ItemRanking], 0)
+	for _, r := range reporting.AllItemRankings() {
+		options = append(options, tap.SelectOption[reporting.ItemRanking]{Value: r, Label: capitalize(r.String())})
+	}
+	ranking := tap.Select(context.Background(), tap.SelectOptions[reporting.ItemRanking]{
+		Message: "Rank items by:",
+		Options: options,
+	})
+	withSpecs := selectWithSpecs()
+
+	clearScreen()
+	fmt.Printf("Top %d items by %s (%s)\n", topItemsLimit, ranking, basis)
+	fmt.Println()
+
+	groupBy := reporting.GroupByItem
+	if withSpecs {
+		groupBy = reporting.GroupByItemVariant
+	}
+	returnRates, ok := metricEstimates(dataset, registry, "item_return_rate", groupBy)
+
+	var items []reporting.ItemStats
+	if ranking == reporting.RankByReturnRate && ok {
+		items = dataset.ItemStats(basis, withSpecs)
+		slices.SortStableFunc(items, func(a, b reporting.ItemStats) int {
+			return cmp.Compare(returnRates[b.Name].Rate(), returnRates[a.Name].Rate())
+		})
+		items = items[:min(topItemsLimit, len(items))]
+	} else {
+		items = dataset.TopItems(ranking, basis, withSpecs, topItemsLimit)
+	}
+
+	currency := dataset.ReportingCurrency()
+	textData := make([][]string, 0)
+	for i, s := range items {
+		row := []string{
+			fmt.Sprintf("%d", i+1),
+			s.Name,
+			fmt.Sprintf("%d", s.Units),
+			formatMoney(currency, s.Revenue.InexactFloat64()),
+			"n/a",
+			formatMoney(currency, s.Commission.InexactFloat64()),
+		}
+		if !ok {
+			textData = append(textData, row)
+			continue
+		}
+		e := returnRates[s.Name]
+		row[4] = fmt.Sprintf("%.2f%%", 100*e.Rate())
+		textData = append(textData, greyRowIfUnreliable(row, e))
+	}
+
+	tap.Table(
+		[]string{"#", "Item", "Units", "Revenue", "Return rate", "Commission"},
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold, MaxWidth: 140})
+	printUnreliableNote()
+}
+
+func renderParetoAnalysis(dataset *reporting.OrderDataset, basis reporting.RevenueBasis) {
+	withSpecs := selectWithSpecs()
+
+	clearScreen()
+	fmt.Printf("Pareto analysis of item revenue (%s)\n", basis)
+	fmt.Println()
+
+	report := dataset.ParetoAnalysis(basis, withSpecs)
+	counts := report.ABCCounts()
+	fmt.Printf("%.2f%% of %d items drive 80%% of revenue\n", 100*report.ItemShareFor80, len(report.Items))
+	fmt.Printf("Class A: %d, class B: %d, class C: %d items\n", counts[reporting.ClassA], counts[reporting.ClassB], counts[reporting.ClassC])
+	fmt.Println()
+
+	currency := dataset.ReportingCurrency()
+	textData := make([][]string, 0)
+	for _, item := range report.Items[:min(topItemsLimit, len(report.Items))] {
+		textData = append(textData, []string{
+			item.Name,
+			string(item.Class),
+			formatMoney(currency, item.Revenue.InexactFloat64()),
+			fmt.Sprintf("%.2f%%", 100*item.RevenueShare),
+			fmt.Sprintf("%.2f%%", 100*item.CumulativeShare),
+		})
+	}
+
+	tap.Table(
+		[]string{"Item", "Class", "Revenue", "Share", "Cumulative"},
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold, MaxWidth: 140})
+
+	renderParetoCurve(report.Curve(20))
+}
+
+func renderParetoCurve(data []reporting.ParetoPoint) {
+	values := make([]barchart.BarData, 0)
+	for _, p := range data {
+		values = append(
+			values,
+			barchart.BarData{
+				Label:  fmt.Sprintf("%.0f%%", 100*p.ItemShare),
+				Values: []barchart.BarValue{{Name: "Cumulative revenue share", Value: p.RevenueShare, Style: blockStyle}}})
+	}
+
+	bc := barchart.New(
+		140, 15,
+		barchart.WithDataSet(values),
+		barchart.WithMaxValue(1))
+
+	bc.Draw()
+
+	fmt.Println(bc.View())
+}
@@ -2,15 +2,25 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/yarlson/tap"
 	"refurbed.com/hackathon/reporting"
 )
 
 func main() {
-	var dataset *reporting.OrderDataset
+	var (
+		dataset  *reporting.OrderDataset
+		fxRates  *reporting.FXRates
+		vatRates *reporting.VATRates
+		metrics  *reporting.MetricRegistry
+		sla      = reporting.DefaultSLAConfig()
+		basis    = reporting.RevenueBasisGross
+	)
 
 	for {
 		clearScreen()
@@ -20,6 +30,42 @@ func main() {
 			spinner := tap.NewSpinner(tap.SpinnerOptions{})
 			spinner.Start("Loading the dataset...")
 
+			var err error
+			fxRates, err = loadFXRates("fx_rates.csv")
+
+			if err != nil {
+				fmt.Printf("Loading the FX rates failed: %v", err)
+				return
+			}
+
+			vatRates, err = loadVATRates("vat_rates.csv")
+
+			if err != nil {
+				fmt.Printf("Loading the VAT rates failed: %v", err)
+				return
+			}
+
+			metrics, err = loadMetricRegistry("metrics.json")
+
+			if err != nil {
+				fmt.Printf("Loading the metric definitions failed: %v", err)
+				return
+			}
+
+			calendar, err := loadHolidays("holidays.csv", "holidays")
+
+			if err != nil {
+				fmt.Printf("Loading the holidays failed: %v", err)
+				return
+			}
+
+			sla, err = loadSLAConfig("sla.csv")
+
+			if err != nil {
+				fmt.Printf("Loading the SLA config failed: %v", err)
+				return
+			}
+
 			in, err := os.Open("orders_v3.csv")
 
 			if err != nil {
@@ -27,7 +73,10 @@ func main() {
 				return
 			}
 
-			dataset, err = reporting.ImportOrderDatasetFromCSV(in)
+			dataset, err = reporting.ImportOrderDatasetFromCSV(in,
+				reporting.WithReportingCurrency(reporting.DefaultCurrency, fxRates),
+				reporting.WithVATRates(vatRates),
+				reporting.WithHolidayCalendar(calendar))
 
 			if err != nil {
 				fmt.Printf("Processing the data failed: %v", err)
@@ -37,16 +86,37 @@ func main() {
 			spinner.Stop("Loading complete", 0)
 		}
 
-		tap.Message(fmt.Sprintf("AOV: %v", dataset.AOV()))
-		tap.Message(fmt.Sprintf("Total revenue: € %.2f", dataset.TotalRevenue().InexactFloat64()))
-		tap.Message(fmt.Sprintf("Delivery median: %v, p95: TODO", dataset.MedianDelivery()))
-		tap.Message(fmt.Sprintf("Return rate: %.2f%%", dataset.ReturnRate().InexactFloat64()))
+		currency := dataset.ReportingCurrency()
+		renderHeadline(dataset, metrics, basis)
 
 		options := []tap.SelectOption[string]{
 			{Value: "RevenueByDay", Label: "Revenue by day", Hint: ""},
 			{Value: "RevenueByWeek", Label: "Revenue by week", Hint: ""},
+			{Value: "RevenueForecast", Label: "Revenue forecast", Hint: "Holt-Winters with prediction intervals"},
+			{Value: "Anomalies", Label: "Anomalies", Hint: "Unusual days in revenue, orders, returns and delivery"},
+			{Value: "WeekdayHourHeatmap", Label: "Weekday × hour heat-map", Hint: "When customers order"},
+			{Value: "OrderValueHistogram", Label: "Order value distribution", Hint: "Histogram and percentiles"},
+			{Value: "TopItems", Label: "Top items", Hint: "By revenue, units, return rate or commission"},
+			{Value: "ParetoAnalysis", Label: "Pareto analysis", Hint: "ABC classes of items by revenue"},
+			{Value: "Pricing", Label: "Pricing", Hint: "Price dispersion and trend per product"},
+			{Value: "SpecBreakdown", Label: "Item specs", Hint: "Return rate and price by storage, colour, grade or battery"},
 			{Value: "ReturnRateByCategory", Label: "Return rate by category", Hint: ""},
+			{Value: "ReturnRateByGrade", Label: "Return rate by condition grade", Hint: "Per top-level category"},
 			{Value: "OrderCountByCategory", Label: "Order count by category and subcategory", Hint: ""},
+			{Value: "FrequentlyBoughtTogether", Label: "Frequently bought together", Hint: "Category and item pairs"},
+			{Value: "Customers", Label: "Customers", Hint: "Repeat purchases and lifetime value"},
+			{Value: "Cohorts", Label: "Cohort retention", Hint: "Monthly customer cohorts"},
+			{Value: "RFMSegments", Label: "RFM segments", Hint: "Recency, frequency, monetary"},
+			{Value: "RefundAnalysis", Label: "Refund analysis", Hint: "Partial vs full refunds"},
+			{Value: "MarketplaceEarnings", Label: "Marketplace earnings", Hint: "Commission and take rate"},
+			{Value: "ShippingSLA", Label: "Shipping SLA", Hint: "Delivery promise compliance per country"},
+			{Value: "PaymentStatuses", Label: "Payment statuses", Hint: "Funnel and breakdown per status"},
+			{Value: "FraudSignals", Label: "Fraud and abuse signals", Hint: "Flagged customers with reasons"},
+			{Value: "DataQuality", Label: "Data quality", Hint: "Validation rule violations"},
+			{Value: "ReportingCurrency", Label: "Reporting currency", Hint: string(currency)},
+			{Value: "RevenueRecognition", Label: "Revenue recognition", Hint: "Payment statuses counted toward revenue"},
+			{Value: "RevenueBasis", Label: "Revenue basis", Hint: basis.String()},
+			{Value: "Metrics", Label: "Metrics", Hint: "Definitions from metrics.json"},
 			{Value: "QueryBuilder", Label: "Query builder", Hint: "Create custom query"},
 			{Value: "Quit", Label: "Quit"},
 		}
@@ -60,13 +130,69 @@ func main() {
 
 		switch result {
 		case "RevenueByDay":
-			renderRevenueByDay(dataset)
+			renderRevenueByDay(dataset, basis, selectComparisons())
 		case "RevenueByWeek":
-			renderRevenueByWeek(dataset)
+			renderRevenueByWeek(dataset, basis, selectComparisons())
+		case "RevenueForecast":
+			renderRevenueForecast(dataset, basis)
+		case "Anomalies":
+			renderAnomalies(dataset)
+		case "WeekdayHourHeatmap":
+			renderWeekdayHourHeatmap(dataset)
+		case "OrderValueHistogram":
+			renderOrderValueHistogram(dataset, basis)
+		case "TopItems":
+			renderTopItems(dataset, metrics, basis)
+		case "ParetoAnalysis":
+			renderParetoAnalysis(dataset, basis)
+		case "Pricing":
+			renderPricing(dataset, basis)
+		case "SpecBreakdown":
+			renderSpecBreakdown(dataset)
 		case "ReturnRateByCategory":
-			renderReturnRateByCategory(dataset)
+			renderReturnRateByCategory(dataset, metrics)
+		case "ReturnRateByGrade":
+			renderReturnRateByGrade(dataset)
 		case "OrderCountByCategory":
 			renderOrderCountByCategory(dataset)
+		case "FrequentlyBoughtTogether":
+			renderFrequentlyBoughtTogether(dataset)
+		case "Customers":
+			renderCustomers(dataset, selectComparisons())
+		case "Cohorts":
+			renderCohorts(dataset)
+		case "RFMSegments":
+			renderRFMSegments(dataset)
+		case "RefundAnalysis":
+			renderRefundAnalysis(dataset)
+		case "MarketplaceEarnings":
+			renderMarketplaceEarnings(dataset, metrics, selectComparisons())
+		case "ShippingSLA":
+			renderShippingSLA(dataset, sla, selectComparisons())
+		case "PaymentStatuses":
+			renderPaymentStatuses(dataset, selectComparisons())
+		case "FraudSignals":
+			renderFraudReport(dataset)
+		case "DataQuality":
+			renderDataQuality(dataset)
+		case "ReportingCurrency":
+			converted, err := selectReportingCurrency(dataset, fxRates)
+			if err != nil {
+				fmt.Printf("Converting the dataset failed: %v\n", err)
+				break
+			}
+			dataset = converted
+			continue
+		case "RevenueRecognition":
+			dataset = selectRecognizedPaymentStatuses(dataset)
+			continue
+		case "RevenueBasis":
+			basis = selectRevenueBasis(dataset, basis)
+			continue
+		case "Metrics":
+			renderMetrics(dataset, metrics)
+		case "QueryBuilder":
+			renderQueryBuilder(dataset, metrics)
 		case "Quit":
 			return
 		}
@@ -75,6 +201,119 @@ func main() {
 	}
 }
 
+func loadFXRates(path string) (*reporting.FXRates, error) {
+	in, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	return reporting.LoadFXRatesFromCSV(in)
+}
+
+func loadVATRates(path string) (*reporting.VATRates, error) {
+	in, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	return reporting.LoadVATRatesFromCSV(in)
+}
+
+func loadSLAConfig(path string) (reporting.SLAConfig, error) {
+	in, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return reporting.DefaultSLAConfig(), nil
+	}
+	if err != nil {
+		return reporting.SLAConfig{}, err
+	}
+	defer in.Close()
+
+	return reporting.LoadSLAConfigFromCSV(in)
+}
+
+// loadHolidays reads the holidays of all countries from path and those of one
+// country from dir/<country>.csv, for example holidays/DE.csv. Missing files
+// leave the calendar with weekends only.
+func loadHolidays(path, dir string) (*reporting.HolidayCalendar, error) {
+	in, err := os.Open(path)
+	var cal *reporting.HolidayCalendar
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		cal = reporting.NewHolidayCalendar()
+	case err != nil:
+		return nil, err
+	default:
+		cal, err = reporting.LoadHolidaysFromCSV(in)
+		in.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.csv"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		in, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		country := strings.TrimSuffix(filepath.Base(file), ".csv")
+		err = cal.AddFromCSV(in, country)
+		in.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	return cal, nil
+}
+
+func selectRevenueBasis(dataset *reporting.OrderDataset, current reporting.RevenueBasis) reporting.RevenueBasis {
+	if !dataset.HasVATRates() {
+		tap.Message("Net revenue needs a vat_rates.csv file next to the dataset")
+		tap.Text(context.Background(), tap.TextOptions{Message: "Press Enter to return to menu"})
+		return reporting.RevenueBasisGross
+	}
+
+	return tap.Select(context.Background(), tap.SelectOptions[reporting.RevenueBasis]{
+		Message: "Select the revenue basis:",
+		Options: []tap.SelectOption[reporting.RevenueBasis]{
+			{Value: reporting.RevenueBasisGross, Label: "Gross", Hint: "including VAT"},
+			{Value: reporting.RevenueBasisNet, Label: "Net", Hint: "excluding VAT"},
+		},
+		InitialValue: &current,
+	})
+}
+
+func selectReportingCurrency(dataset *reporting.OrderDataset, fxRates *reporting.FXRates) (*reporting.OrderDataset, error) {
+	options := make([]tap.SelectOption[reporting.Currency], 0)
+	for _, c := range fxRates.Currencies() {
+		options = append(options, tap.SelectOption[reporting.Currency]{Value: c, Label: string(c), Hint: c.Symbol()})
+	}
+
+	current := dataset.ReportingCurrency()
+	currency := tap.Select(context.Background(), tap.SelectOptions[reporting.Currency]{
+		Message:      "Select the reporting currency:",
+		Options:      options,
+		InitialValue: &current,
+	})
+	if currency == "" || currency == current {
+		return dataset, nil
+	}
+
+	return dataset.InCurrency(currency, fxRates)
+}
+
 func clearScreen() {
 	fmt.Print("\033[H\033[2J")
 }
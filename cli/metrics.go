@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/yarlson/tap"
+	"refurbed.com/hackathon/reporting"
+)
+
+const queryExportPath = "query_results.csv"
+
+func loadMetricRegistry(path string) (*reporting.MetricRegistry, error) {
+	in, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return reporting.DefaultMetricRegistry(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	return reporting.LoadMetricRegistryFromJSON(in)
+}
+
+func formatMetric(m *reporting.Metric, currency reporting.Currency, v float64) string {
+	switch m.Unit {
+	case reporting.MetricUnitMoney:
+		return formatMoney(currency, v)
+	case reporting.MetricUnitPercent:
+		return fmt.Sprintf("%.2f%%", 100*v)
+	default:
+		return fmt.Sprintf("%.2f", v)
+	}
+}
+
+// renderHeadline prints the main menu's key numbers as defined in the metric
+// registry.
+func renderHeadline(dataset *reporting.OrderDataset, registry *reporting.MetricRegistry, basis reporting.RevenueBasis) {
+	revenue, aov := "revenue", "aov"
+	if basis == reporting.RevenueBasisNet {
+		revenue, aov = "net_revenue", "net_aov"
+	}
+	tap.Message(fmt.Sprintf("AOV (%s): %s", basis, evaluateMetric(dataset, registry, aov)))
+	tap.Message(fmt.Sprintf("Total revenue (%s): %s", basis, evaluateMetric(dataset, registry, revenue)))
+	tap.Message(fmt.Sprintf("Delivery median: %v (%.1f business days), p95: %v (%.1f business days)",
+		dataset.MedianDelivery(), dataset.MedianDeliveryBusinessDays(),
+		dataset.DeliveryPercentile(95).Round(time.Minute), dataset.DeliveryBusinessDaysPercentile(95)))
+	tap.Message(fmt.Sprintf("Return rate: %s of items, %s of orders",
+		evaluateMetric(dataset, registry, "item_return_rate"), evaluateMetric(dataset, registry, "order_return_rate")))
+}
+
+func evaluateMetric(dataset *reporting.OrderDataset, registry *reporting.MetricRegistry, name string) string {
+	m, ok := registry.Lookup(name)
+	if !ok {
+		return fmt.Sprintf("n/a (no metric %q)", name)
+	}
+	if e, ok := dataset.EvaluateEstimate(m); ok {
+		return formatRateEstimate(e)
+	}
+	return formatMetric(m, dataset.ReportingCurrency(), dataset.Evaluate(m).InexactFloat64())
+}
+
+// metricColumn formats the metric of a group, "n/a" for groups without items
+// or when the registry has no such metric.
+func metricColumn(dataset *reporting.OrderDataset, registry *reporting.MetricRegistry, name string, groupBy reporting.MetricGrouping) func(group string) string {
+	m, ok := registry.Lookup(name)
+	if !ok {
+		return func(string) string { return fmt.Sprintf("n/a (no metric %q)", name) }
+	}
+	rows := map[string]reporting.MetricRow{}
+	for _, r := range dataset.Query(reporting.MetricQuery{Metric: m, GroupBy: groupBy}) {
+		rows[r.Group] = r
+	}
+	return func(group string) string {
+		r, ok := rows[group]
+		if !ok {
+			return "n/a"
+		}
+		if e, ok := m.RateEstimate(r); ok {
+			return formatRateEstimate(e)
+		}
+		return formatMetric(m, dataset.ReportingCurrency(), r.Value.InexactFloat64())
+	}
+}
+
+// metricEstimates returns the share metric per group with its interval. ok is
+// false when the registry has no such share metric.
+func metricEstimates(dataset *reporting.OrderDataset, registry *reporting.MetricRegistry, name string, groupBy reporting.MetricGrouping) (map[string]reporting.RateEstimate, bool) {
+	m, ok := registry.Lookup(name)
+	if !ok {
+		return nil, false
+	}
+	estimates := map[string]reporting.RateEstimate{}
+	for _, r := range dataset.Query(reporting.MetricQuery{Metric: m, GroupBy: groupBy}) {
+		e, ok := m.RateEstimate(r)
+		if !ok {
+			return nil, false
+		}
+		estimates[r.Group] = e
+	}
+	return estimates, true
+}
+
+func renderMetrics(dataset *reporting.OrderDataset, registry *reporting.MetricRegistry) {
+	fmt.Println("Metrics")
+	fmt.Println()
+
+	textData := make([][]string, 0)
+	for _, m := range registry.All() {
+		textData = append(textData, []string{
+			m.Name,
+			formatMetric(m, dataset.ReportingCurrency(), dataset.Evaluate(m).InexactFloat64()),
+			m.Description,
+		})
+	}
+
+	tap.Table(
+		[]string{"Metric", "Value", "Definition"},
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold, MaxWidth: 140})
+}
+
+func renderQueryBuilder(dataset *reporting.OrderDataset, registry *reporting.MetricRegistry) {
+	metrics := make([]tap.SelectOption[*reporting.Metric], 0)
+	for _, m := range registry.All() {
+		metrics = append(metrics, tap.SelectOption[*reporting.Metric]{Value: m, Label: m.Name, Hint: m.Description})
+	}
+	metric := tap.Select(context.Background(), tap.SelectOptions[*reporting.Metric]{
+		Message: "Select the metric:",
+		Options: metrics,
+	})
+	if metric == nil {
+		return
+	}
+
+	groupings := make([]tap.SelectOption[reporting.MetricGrouping], 0)
+	for _, g := range []reporting.MetricGrouping{reporting.GroupByNone, reporting.GroupByDay, reporting.GroupByWeek, reporting.GroupByCountry, reporting.GroupByCategory, reporting.GroupByItem, reporting.GroupByItemVariant} {
+		groupings = append(groupings, tap.SelectOption[reporting.MetricGrouping]{Value: g, Label: capitalize(g.String())})
+	}
+	grouping := tap.Select(context.Background(), tap.SelectOptions[reporting.MetricGrouping]{
+		Message: "Group by:",
+		Options: groupings,
+	})
+
+	query := reporting.MetricQuery{
+		Metric:  metric,
+		GroupBy: grouping,
+		Filter:  selectItemFilter(dataset),
+	}
+	if grouping == reporting.GroupByDay {
+		_, latest := dataset.DateRange()
+		latest = time.Date(latest.Year(), latest.Month(), latest.Day(), 0, 0, 0, 0, time.UTC)
+		query.Start, query.End = latest.AddDate(0, 0, -28), latest
+	}
+	rows := dataset.Query(query)
+
+	clearScreen()
+	fmt.Printf("%s by %s (%s)\n", metric.Name, grouping, describeItemFilter(query.Filter))
+	fmt.Println()
+
+	textData := make([][]string, 0)
+	for _, r := range rows {
+		textData = append(textData, []string{
+			r.Group,
+			formatMetric(metric, dataset.ReportingCurrency(), r.Value.InexactFloat64()),
+			fmt.Sprintf("%d", r.Count),
+		})
+	}
+	tap.Table(
+		[]string{capitalize(grouping.String()), metric.Name, capitalize(string(metric.Level)) + "s"},
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold, MaxWidth: 140})
+
+	export := tap.Confirm(context.Background(), tap.ConfirmOptions{
+		Message:  fmt.Sprintf("Export %d rows to %s?", len(rows), queryExportPath),
+		Active:   "Yes",
+		Inactive: "No",
+	})
+	if !export {
+		return
+	}
+	if err := exportMetricRows(queryExportPath, query, rows); err != nil {
+		fmt.Printf("Exporting the query failed: %v\n", err)
+		return
+	}
+	fmt.Printf("Exported to %s\n", queryExportPath)
+}
+
+func exportMetricRows(path string, query reporting.MetricQuery, rows []reporting.MetricRow) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := reporting.WriteMetricRowsCSV(out, query, rows); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NimbleMarkets/ntcharts/barchart"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yarlson/tap"
+	"refurbed.com/hackathon/reporting"
+)
+
+var paymentStatusBlockStyles = map[reporting.PaymentStatus]lipgloss.Style{
+	reporting.PaymentStatusPaid:      lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Background(lipgloss.Color("2")),
+	reporting.PaymentStatusRefunded:  lipgloss.NewStyle().Foreground(lipgloss.Color("5")).Background(lipgloss.Color("5")),
+	reporting.PaymentStatusPending:   lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Background(lipgloss.Color("3")),
+	reporting.PaymentStatusFailed:    lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Background(lipgloss.Color("1")),
+	reporting.PaymentStatusCancelled: lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Background(lipgloss.Color("8")),
+	reporting.PaymentStatusUnknown:   lipgloss.NewStyle().Foreground(lipgloss.Color("7")).Background(lipgloss.Color("7")),
+}
+
+func renderPaymentStatuses(dataset *reporting.OrderDataset, comparisons []reporting.Comparison) {
+	fmt.Println("Payment statuses")
+	fmt.Println()
+
+	currency := dataset.ReportingCurrency()
+	renderPaymentFunnelTable(dataset.PaymentFunnel(), currency)
+	renderPaymentStatusTable(dataset.PaymentStatusBreakdown(), currency)
+
+	_, latest := dataset.DateRange()
+	latest = time.Date(latest.Year(), latest.Month(), latest.Day(), 0, 0, 0, 0, time.UTC)
+	byWeek := dataset.PaymentStatusByWeek(latest.AddDate(0, 0, -(7*7)-1), latest.AddDate(0, 0, -1), comparisons...)
+
+	renderPaymentStatusByWeekTable(byWeek, comparisons)
+	renderPaymentStatusByWeekGraph(byWeek)
+}
+
+func renderPaymentFunnelTable(data []reporting.PaymentFunnelStage, currency reporting.Currency) {
+	textData := make([][]string, 0)
+	for _, s := range data {
+		conversion := 0.0
+		if data[0].Orders > 0 {
+			conversion = 100 * float64(s.Orders) / float64(data[0].Orders)
+		}
+		textData = append(textData, []string{
+			s.Name,
+			fmt.Sprintf("%d", s.Orders),
+			fmt.Sprintf("%.2f%%", conversion),
+			formatMoney(currency, s.Value.InexactFloat64()),
+		})
+	}
+
+	tap.Table(
+		[]string{"Stage", "Orders", "Of placed", "Value"},
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold})
+}
+
+func renderPaymentStatusTable(data []reporting.PaymentStatusTotals, currency reporting.Currency) {
+	textData := make([][]string, 0)
+	for _, t := range data {
+		textData = append(textData, []string{
+			t.Status.String(),
+			fmt.Sprintf("%d", t.Orders),
+			fmt.Sprintf("%d", t.Items),
+			formatMoney(currency, t.Value.InexactFloat64()),
+		})
+	}
+
+	tap.Table(
+		[]string{"Status", "Orders", "Items", "Value"},
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold})
+}
+
+// renderPaymentStatusByWeekTable compares the paid orders of every week with
+// the given comparisons.
+func renderPaymentStatusByWeekTable(data []reporting.PaymentStatusPeriod, comparisons []reporting.Comparison) {
+	headers := []string{"Week"}
+	for _, status := range reporting.AllPaymentStatuses() {
+		headers = append(headers, status.String())
+	}
+	headers = append(headers, comparisonHeaders(comparisons)...)
+
+	textData := make([][]string, 0)
+	for _, p := range data {
+		row := []string{p.Title}
+		for _, t := range p.ByStatus {
+			row = append(row, fmt.Sprintf("%d", t.Orders))
+		}
+		textData = append(textData, append(row, comparisonCells(p.Compared, formatCount, formatSignedCount)...))
+	}
+
+	tap.Table(
+		headers,
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold, MaxWidth: 140})
+}
+
+func renderPaymentStatusByWeekGraph(data []reporting.PaymentStatusPeriod) {
+	values := make([]barchart.BarData, 0)
+	for _, p := range data {
+		bar := barchart.BarData{Label: p.Start.Format("01-02")}
+		for _, t := range p.ByStatus {
+			bar.Values = append(bar.Values, barchart.BarValue{Name: t.Status.String(), Value: float64(t.Orders), Style: paymentStatusBlockStyles[t.Status]})
+		}
+		values = append(values, bar)
+		values = append(values, comparisonBars(p.Compared)...)
+	}
+
+	bc := barchart.New(140, 15)
+	bc.SetShowAxis(true)
+	bc.PushAll(values)
+	bc.Draw()
+
+	fmt.Println(bc.View())
+}
+
+func selectRecognizedPaymentStatuses(dataset *reporting.OrderDataset) *reporting.OrderDataset {
+	options := make([]tap.SelectOption[reporting.PaymentStatus], 0)
+	for _, status := range reporting.AllPaymentStatuses() {
+		options = append(options, tap.SelectOption[reporting.PaymentStatus]{Value: status, Label: status.String()})
+	}
+
+	statuses := tap.MultiSelect(context.Background(), tap.MultiSelectOptions[reporting.PaymentStatus]{
+		Message:       "Payment statuses counted toward revenue (space to toggle):",
+		Options:       options,
+		InitialValues: dataset.RecognizedPaymentStatuses(),
+	})
+	if len(statuses) == 0 {
+		return dataset
+	}
+	return dataset.WithRecognizedPaymentStatuses(statuses...)
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/NimbleMarkets/ntcharts/barchart"
+	"github.com/yarlson/tap"
+	"refurbed.com/hackathon/reporting"
+)
+
+const priceBands = 4
+
+func selectProduct(dataset *reporting.OrderDataset, withSpecs bool) string {
+	search := tap.Text(context.Background(), tap.TextOptions{
+		Message:     "Search product (empty for all):",
+		Placeholder: "iPhone 13",
+	})
+	search = strings.ToLower(strings.TrimSpace(search))
+
+	options := make([]tap.SelectOption[string], 0)
+	for _, name := range dataset.AllItemNames(withSpecs) {
+		if strings.Contains(strings.ToLower(name), search) {
+			options = append(options, tap.SelectOption[string]{Value: name, Label: name})
+		}
+	}
+	if len(options) == 0 {
+		tap.Message(fmt.Sprintf("No product matches %q", search))
+		return ""
+	}
+	return tap.Select(context.Background(), tap.SelectOptions[string]{
+		Message: "Select the product:",
+		Options: options,
+	})
+}
+
+func renderPricing(dataset *reporting.OrderDataset, basis reporting.RevenueBasis) {
+	withSpecs := selectWithSpecs()
+	currency := dataset.ReportingCurrency()
+
+	clearScreen()
+	fmt.Printf("Price dispersion (%s)\n", basis)
+	fmt.Println()
+	renderPriceSummaryTable(dataset.PriceSummaries(basis, withSpecs), currency)
+
+	name := selectProduct(dataset, withSpecs)
+	if name == "" {
+		return
+	}
+
+	clearScreen()
+	fmt.Printf("Prices of %s (%s)\n", name, basis)
+	fmt.Println()
+
+	summary := dataset.PriceSummary(name, basis, withSpecs)
+	fmt.Printf("Items: %d, min: %s, median: %s, max: %s, dispersion: %.2f%%\n",
+		summary.Items,
+		formatMoney(currency, summary.Min.InexactFloat64()),
+		formatMoney(currency, summary.Median.InexactFloat64()),
+		formatMoney(currency, summary.Max.InexactFloat64()),
+		100*summary.Dispersion())
+	fmt.Println()
+
+	trend := dataset.PriceByWeek(name, basis, withSpecs)
+	renderPriceTrendTable(trend, currency)
+	renderPriceTrendGraph(trend)
+	renderPriceBandTable(dataset.RefundRateByPriceBand(name, basis, withSpecs, priceBands), currency)
+}
+
+func renderPriceSummaryTable(data []reporting.PriceSummary, currency reporting.Currency) {
+	textData := make([][]string, 0)
+	for _, s := range data {
+		textData = append(textData, []string{
+			s.Name,
+			fmt.Sprintf("%d", s.Items),
+			formatMoney(currency, s.Min.InexactFloat64()),
+			formatMoney(currency, s.Median.InexactFloat64()),
+			formatMoney(currency, s.Max.InexactFloat64()),
+			fmt.Sprintf("%.2f%%", 100*s.Dispersion()),
+		})
+	}
+
+	tap.Table(
+		[]string{"Product", "Items", "Min", "Median", "Max", "Dispersion"},
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold, MaxWidth: 140})
+}
+
+func renderPriceTrendTable(data []reporting.PricePoint, currency reporting.Currency) {
+	textData := make([][]string, 0)
+	for _, p := range data {
+		if p.Items == 0 {
+			textData = append(textData, []string{p.Title, "0", "-", "-", "-"})
+			continue
+		}
+		textData = append(textData, []string{
+			p.Title,
+			fmt.Sprintf("%d", p.Items),
+			formatMoney(currency, p.Min.InexactFloat64()),
+			formatMoney(currency, p.Median.InexactFloat64()),
+			formatMoney(currency, p.Max.InexactFloat64()),
+		})
+	}
+
+	tap.Table(
+		[]string{"Week", "Items", "Min", "Median", "Max"},
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold, MaxWidth: 140})
+}
+
+func renderPriceTrendGraph(data []reporting.PricePoint) {
+	values := make([]barchart.BarData, 0)
+	for _, p := range data {
+		values = append(
+			values,
+			barchart.BarData{
+				Label:  p.Start.Format("01-02"),
+				Values: []barchart.BarValue{{Name: "Median price", Value: p.Median.InexactFloat64(), Style: blockStyle}}})
+	}
+
+	bc := barchart.New(
+		140, 15,
+		barchart.WithDataSet(values))
+
+	bc.Draw()
+
+	fmt.Println(bc.View())
+}
+
+func renderPriceBandTable(data []reporting.PriceBand, currency reporting.Currency) {
+	textData := make([][]string, 0)
+	for _, b := range data {
+		textData = append(textData, greyRowIfUnreliable([]string{
+			fmt.Sprintf("%s – %s", formatMoney(currency, b.Lower.InexactFloat64()), formatMoney(currency, b.Upper.InexactFloat64())),
+			fmt.Sprintf("%d", b.Refund.Trials),
+			formatRateEstimate(b.Refund),
+		}, b.Refund))
+	}
+
+	tap.Table(
+		[]string{"Price band", "Items", "Refund rate"},
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold, MaxWidth: 140})
+	printUnreliableNote()
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/yarlson/tap"
+	"refurbed.com/hackathon/reporting"
+)
+
+func renderDataQuality(dataset *reporting.OrderDataset) {
+	fmt.Println("Data quality")
+	fmt.Println()
+
+	report := dataset.DataQuality()
+	if report == nil {
+		fmt.Println("No data quality report available")
+		return
+	}
+
+	fmt.Printf("Checked %d order items: %d errors, %d warnings, %d info\n\n",
+		report.CheckedItems,
+		report.NumViolations(reporting.SeverityError),
+		report.NumViolations(reporting.SeverityWarning),
+		report.NumViolations(reporting.SeverityInfo))
+
+	renderDataQualityTable(report)
+	renderDataQualityExamplesTable(report)
+}
+
+func renderDataQualityTable(report *reporting.DataQualityReport) {
+	textData := make([][]string, 0)
+	for _, res := range report.Results {
+		share := 0.0
+		if report.CheckedItems > 0 {
+			share = 100 * float64(res.Violations) / float64(report.CheckedItems)
+		}
+		textData = append(textData, []string{
+			res.Rule.Name,
+			res.Rule.Severity.String(),
+			res.Rule.Description,
+			fmt.Sprintf("%d", res.Violations),
+			fmt.Sprintf("%.2f%%", share),
+		})
+	}
+
+	tap.Table(
+		[]string{"Rule", "Severity", "Description", "Violations", "Share"},
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold, MaxWidth: 140})
+}
+
+func renderDataQualityExamplesTable(report *reporting.DataQualityReport) {
+	textData := make([][]string, 0)
+	for _, res := range report.Results {
+		for _, ex := range res.Examples {
+			textData = append(textData, []string{
+				res.Rule.Name,
+				fmt.Sprintf("%d", ex.Row),
+				string(ex.OrderID),
+				ex.ItemName,
+			})
+		}
+	}
+	if len(textData) == 0 {
+		return
+	}
+
+	tap.Table(
+		[]string{"Rule", "Row", "Order", "Item"},
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold, MaxWidth: 140})
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/NimbleMarkets/ntcharts/barchart"
+	"github.com/yarlson/tap"
+	"refurbed.com/hackathon/reporting"
+)
+
+func renderRefundAnalysis(dataset *reporting.OrderDataset) {
+	fmt.Println("Refund analysis")
+	fmt.Println()
+
+	currency := dataset.ReportingCurrency()
+	summary := dataset.RefundSummary()
+	fmt.Printf("Refunded items: %d of %d (%s), value refunded: %s (%s)\n",
+		summary.Refunds(), summary.Items, formatRateEstimate(summary.RefundCountEstimate()),
+		formatMoney(currency, summary.Refunded.InexactFloat64()), formatRateEstimate(summary.RefundValueEstimate()))
+	fmt.Printf("Partial: %d, full: %d, over-refund: %d\n", summary.Partial, summary.Full, summary.Over)
+	fmt.Println()
+
+	byCategory := dataset.RefundsByCategory()
+	renderRefundTable("Category", byCategory, currency)
+	renderRefundGraph(byCategory)
+	renderRefundTable("Country", dataset.RefundsByCountry(), currency)
+	printUnreliableNote()
+}
+
+func renderRefundTable(title string, data []reporting.RefundBreakdown, currency reporting.Currency) {
+	textData := make([][]string, 0)
+	for _, b := range data {
+		textData = append(textData, greyRowIfUnreliable([]string{
+			b.Title,
+			fmt.Sprintf("%d", b.Items),
+			fmt.Sprintf("%d", b.Partial),
+			fmt.Sprintf("%d", b.Full),
+			fmt.Sprintf("%d", b.Over),
+			formatRateEstimate(b.RefundCountEstimate()),
+			formatRateEstimate(b.FullRefundCountEstimate()),
+			formatMoney(currency, b.Refunded.InexactFloat64()),
+			formatRateEstimate(b.RefundValueEstimate()),
+		}, b.RefundCountEstimate()))
+	}
+
+	tap.Table(
+		[]string{title, "Items", "Partial", "Full", "Over", "Count rate", "Full rate", "Refunded", "Value rate"},
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold, MaxWidth: 160})
+}
+
+func renderRefundGraph(data []reporting.RefundBreakdown) {
+	values := make([]barchart.BarData, 0)
+	for _, b := range data {
+		values = append(
+			values,
+			barchart.BarData{
+				Label:  b.Title,
+				Values: []barchart.BarValue{{Name: "Refund value rate", Value: b.RefundValueRate(), Style: blockStyle}}})
+	}
+
+	bc := barchart.New(
+		140, 15,
+		barchart.WithDataSet(values))
+
+	bc.Draw()
+
+	fmt.Println(bc.View())
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/NimbleMarkets/ntcharts/barchart"
@@ -19,44 +21,107 @@ var blockStyle = lipgloss.NewStyle().
 	Foreground(lipgloss.Color("3")).
 	Background(lipgloss.Color("3"))
 
-func renderRevenueByDay(dataset *reporting.OrderDataset) {
-	fmt.Println("Revenue by day")
+var comparisonBlockStyles = map[reporting.Comparison]lipgloss.Style{
+	reporting.ComparisonPreviousPeriod: lipgloss.NewStyle().
+		Foreground(lipgloss.Color("4")).
+		Background(lipgloss.Color("4")),
+	reporting.ComparisonPreviousYear: lipgloss.NewStyle().
+		Foreground(lipgloss.Color("5")).
+		Background(lipgloss.Color("5")),
+}
+
+func selectComparisons() []reporting.Comparison {
+	return tap.MultiSelect(context.Background(), tap.MultiSelectOptions[reporting.Comparison]{
+		Message: "Compare with (space to toggle, enter to continue):",
+		Options: []tap.SelectOption[reporting.Comparison]{
+			{Value: reporting.ComparisonPreviousPeriod, Label: "Previous period"},
+			{Value: reporting.ComparisonPreviousYear, Label: "Same period last year", Hint: "52 weeks earlier"},
+		},
+	})
+}
+
+func renderRevenueByDay(dataset *reporting.OrderDataset, basis reporting.RevenueBasis, comparisons []reporting.Comparison) {
+	fmt.Printf("Revenue by day (%s)\n", basis)
 	fmt.Println()
 
 	_, latest := dataset.DateRange()
 	latest = time.Date(latest.Year(), latest.Month(), latest.Day(), 0, 0, 0, 0, time.UTC)
 
-	data := make([]stat, 0)
-	rbd := dataset.RevenueByDay(latest.AddDate(0, 0, -8), latest.AddDate(0, 0, -1))
+	start, end := latest.AddDate(0, 0, -8), latest.AddDate(0, 0, -1)
+	rbd := dataset.RevenueByDayFor(basis, start, end, comparisons...)
+	anomalies := revenueAnomalies(dataset, start, end)
 
-	for _, r := range rbd {
-		data = append(data, stat{r.Title, r.Revenue.InexactFloat64()})
+	holidays := map[time.Time][]string{}
+	for _, d := range rbd {
+		if names := dataset.Calendar().HolidaysOn(d.Start); len(names) > 0 {
+			holidays[d.Start] = names
+		}
 	}
 
-	renderRevenueByDayTable(data)
-	renderRevenueByDayGraph(data)
+	renderRevenueTable("Day", rbd, comparisons, anomalies, holidays, dataset.ReportingCurrency())
+	renderRevenueGraph(rbd, anomalies, holidays)
 }
 
-func renderRevenueByDayTable(data []stat) {
+func renderRevenueByWeek(dataset *reporting.OrderDataset, basis reporting.RevenueBasis, comparisons []reporting.Comparison) {
+	fmt.Printf("Revenue by week (%s)\n", basis)
+	fmt.Println()
+
+	_, latest := dataset.DateRange()
+	latest = time.Date(latest.Year(), latest.Month(), latest.Day(), 0, 0, 0, 0, time.UTC)
+
+	rbw := dataset.RevenueByWeekFor(basis, latest.AddDate(0, 0, -(7*7)-1), latest.AddDate(0, 0, -1), comparisons...)
+
+	renderRevenueTable("Week", rbw, comparisons, nil, nil, dataset.ReportingCurrency())
+	renderRevenueGraph(rbw, nil, nil)
+}
+
+func renderRevenueTable(title string, data []reporting.IntervalRevenue, comparisons []reporting.Comparison, anomalies map[time.Time]reporting.Anomaly, holidays map[time.Time][]string, currency reporting.Currency) {
+	headers := []string{title, "Revenue"}
+	if holidays != nil {
+		headers = append(headers, "Holidays")
+	}
+	headers = append(headers, comparisonHeaders(comparisons)...)
+
+	money := func(v float64) string { return formatMoney(currency, v) }
 	textData := make([][]string, 0)
 	for _, d := range data {
-		textData = append(textData, []string{d.x, fmt.Sprintf("€ %f", d.y)})
+		row := []string{d.Title, formatMoney(currency, d.Revenue.InexactFloat64())}
+		if a, ok := anomalies[d.Start]; ok {
+			row[0] = anomalyStyle.Render(fmt.Sprintf("%s ! %+.1f", d.Title, a.Score))
+		}
+		if holidays != nil {
+			row = append(row, strings.Join(holidays[d.Start], ", "))
+		}
+		row = append(row, comparisonCells(d.Compared, money, formatSignedAmount)...)
+		textData = append(textData, row)
 	}
 
 	tap.Table(
-		[]string{"Day", "Revenue"},
+		headers,
 		textData,
-		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold})
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold, MaxWidth: 160})
 }
 
-func renderRevenueByDayGraph(data []stat) {
+// renderRevenueGraph draws every interval next to the intervals it is
+// compared with. Anomalous intervals are drawn in red, holidays are marked
+// with a * after the date.
+func renderRevenueGraph(data []reporting.IntervalRevenue, anomalies map[time.Time]reporting.Anomaly, holidays map[time.Time][]string) {
 	values := make([]barchart.BarData, 0)
-	for _, stat := range data {
+	for _, d := range data {
+		style := blockStyle
+		if _, ok := anomalies[d.Start]; ok {
+			style = anomalyBlockStyle
+		}
+		label := d.Start.Format("01-02")
+		if len(holidays[d.Start]) > 0 {
+			label += "*"
+		}
 		values = append(
 			values,
 			barchart.BarData{
-				Label:  stat.x,
-				Values: []barchart.BarValue{{Name: "Revenue", Value: stat.y, Style: blockStyle}}})
+				Label:  label,
+				Values: []barchart.BarValue{{Name: "Revenue", Value: d.Revenue.InexactFloat64(), Style: style}}})
+		values = append(values, comparisonBars(d.Compared)...)
 	}
 
 	bc := barchart.New(140, 15)
@@ -67,78 +132,113 @@ func renderRevenueByDayGraph(data []stat) {
 	fmt.Println(bc.View())
 }
 
-func renderRevenueByWeek(dataset *reporting.OrderDataset) {
-	fmt.Println("Revenue by week")
-	fmt.Println()
-
-	_, latest := dataset.DateRange()
-	latest = time.Date(latest.Year(), latest.Month(), latest.Day(), 0, 0, 0, 0, time.UTC)
+// comparisonHeaders are the table columns filled by comparisonCells.
+func comparisonHeaders(comparisons []reporting.Comparison) []string {
+	headers := make([]string, 0, 3*len(comparisons))
+	for _, c := range comparisons {
+		headers = append(headers, capitalize(c.String()), "Δ", "Δ%")
+	}
+	return headers
+}
 
-	data := make([]stat, 0)
-	rbd := dataset.RevenueByWeek(latest.AddDate(0, 0, -(7*7)-1), latest.AddDate(0, 0, -1))
+// comparisonCells formats the compared value, the delta and the relative delta
+// of every comparison of an interval.
+func comparisonCells(compared []reporting.ComparedValue, formatValue, formatDelta func(float64) string) []string {
+	cells := make([]string, 0, 3*len(compared))
+	for _, c := range compared {
+		deltaPercent := "n/a"
+		if pct, ok := c.DeltaPercent(); ok {
+			deltaPercent = fmt.Sprintf("%+.1f%%", pct)
+		}
+		cells = append(cells, formatValue(c.Value.InexactFloat64()), formatDelta(c.Delta.InexactFloat64()), deltaPercent)
+	}
+	return cells
+}
 
-	for _, r := range rbd {
-		data = append(data, stat{r.Title, r.Revenue.InexactFloat64()})
+// comparisonBars are drawn right after the bar of the interval they are
+// compared with.
+func comparisonBars(compared []reporting.ComparedValue) []barchart.BarData {
+	bars := make([]barchart.BarData, 0, len(compared))
+	for _, c := range compared {
+		bars = append(
+			bars,
+			barchart.BarData{
+				Values: []barchart.BarValue{{Name: c.Comparison.String(), Value: c.Value.InexactFloat64(), Style: comparisonBlockStyles[c.Comparison]}}})
 	}
+	return bars
+}
 
-	renderRevenueByWeekTable(data)
-	renderRevenueByWeekGraph(data)
+func formatSignedAmount(v float64) string {
+	return fmt.Sprintf("%+.2f", v)
 }
 
-func renderRevenueByWeekTable(data []stat) {
-	textData := make([][]string, 0)
-	for _, d := range data {
-		textData = append(textData, []string{d.x, fmt.Sprintf("€ %f", d.y)})
-	}
+func formatCount(v float64) string {
+	return fmt.Sprintf("%.0f", v)
+}
 
-	tap.Table(
-		[]string{"Week", "Revenue"},
-		textData,
-		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold})
+func formatSignedCount(v float64) string {
+	return fmt.Sprintf("%+.0f", v)
 }
 
-func renderRevenueByWeekGraph(data []stat) {
-	values := make([]barchart.BarData, 0)
-	for _, stat := range data {
-		values = append(
-			values,
-			barchart.BarData{
-				Label:  stat.x,
-				Values: []barchart.BarValue{{Name: "Revenue", Value: stat.y, Style: blockStyle}}})
-	}
+func formatPercent(v float64) string {
+	return fmt.Sprintf("%.2f%%", 100*v)
+}
 
-	bc := barchart.New(140, 15)
-	bc.SetShowAxis(true)
-	bc.PushAll(values)
-	bc.Draw()
+func formatSignedPoints(v float64) string {
+	return fmt.Sprintf("%+.2f pp", 100*v)
+}
 
-	fmt.Println(bc.View())
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
 }
 
-func renderReturnRateByCategory(dataset *reporting.OrderDataset) {
+func renderReturnRateByCategory(dataset *reporting.OrderDataset, registry *reporting.MetricRegistry) {
 	fmt.Println("Return rate by category")
 	fmt.Println()
 
-	data := make([]stat, 0)
+	estimates, ok := metricEstimates(dataset, registry, "item_return_rate", reporting.GroupByCategory)
+	if !ok {
+		tap.Message("The return rate needs an item_return_rate share metric in metrics.json")
+		return
+	}
 
+	data := make([]stat, 0)
+	segments := make([]string, 0)
 	for _, c := range dataset.AllCategories() {
-		data = append(data, stat{string(c), dataset.ReturnRateByCategory(c)})
+		e, ok := estimates[string(c)]
+		if !ok {
+			e = reporting.NewRateEstimate(0, 0)
+			estimates[string(c)] = e
+		}
+		data = append(data, stat{string(c), e.Rate()})
+		segments = append(segments, string(c))
 	}
 
-	renderReturnRateByCategoryTable(data)
+	renderReturnRateByCategoryTable(segments, estimates)
 	renderReturnRateByCategoryGraph(data)
+	selectSegmentComparison(segments, estimates)
 }
 
-func renderReturnRateByCategoryTable(data []stat) {
+func renderReturnRateByCategoryTable(segments []string, estimates map[string]reporting.RateEstimate) {
 	textData := make([][]string, 0)
-	for _, d := range data {
-		textData = append(textData, []string{d.x, fmt.Sprintf("%d%%", (int)(100*d.y))})
+	for _, s := range segments {
+		e := estimates[s]
+		textData = append(textData, greyRowIfUnreliable([]string{
+			s,
+			fmt.Sprintf("%.2f%%", 100*e.Rate()),
+			fmt.Sprintf("%.1f–%.1f%%", 100*e.Lower, 100*e.Upper),
+			fmt.Sprintf("%d", e.Trials),
+		}, e))
 	}
 
 	tap.Table(
-		[]string{"Category", "Return rate"},
+		[]string{"Category", "Return rate", "95% CI", "Items"},
 		textData,
 		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold})
+	printUnreliableNote()
 }
 
 func renderReturnRateByCategoryGraph(data []stat) {
@@ -214,3 +314,7 @@ func renderOrderCountByCategoryGraph(data []stat) {
 
 	fmt.Println(bc.View())
 }
+
+func formatMoney(currency reporting.Currency, v float64) string {
+	return fmt.Sprintf("%s %.2f", currency.Symbol(), v)
+}
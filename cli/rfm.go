@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/yarlson/tap"
+	"refurbed.com/hackathon/reporting"
+)
+
+const rfmExportPath = "rfm_segments.csv"
+
+func renderRFMSegments(dataset *reporting.OrderDataset) {
+	fmt.Println("RFM customer segments")
+	fmt.Println()
+
+	cfg := dataset.DefaultRFMConfig()
+	scores := dataset.RFMScores(cfg)
+
+	renderRFMSegmentsTable(reporting.SummarizeRFMSegments(scores, cfg.Segments), dataset.ReportingCurrency())
+
+	export := tap.Confirm(context.Background(), tap.ConfirmOptions{
+		Message:  fmt.Sprintf("Export the segment membership of %d customers to %s?", len(scores), rfmExportPath),
+		Active:   "Yes",
+		Inactive: "No",
+	})
+	if !export {
+		return
+	}
+	if err := exportRFMScores(rfmExportPath, scores); err != nil {
+		fmt.Printf("Exporting the segments failed: %v\n", err)
+		return
+	}
+	fmt.Printf("Exported to %s\n", rfmExportPath)
+}
+
+func renderRFMSegmentsTable(data []reporting.RFMSegmentSummary, currency reporting.Currency) {
+	textData := make([][]string, 0)
+	for _, s := range data {
+		textData = append(textData, []string{
+			s.Name,
+			fmt.Sprintf("%d", s.Customers),
+			fmt.Sprintf("%.2f%%", 100*s.CustomerShare),
+			formatMoney(currency, s.Revenue.InexactFloat64()),
+			fmt.Sprintf("%.2f%%", 100*s.RevenueShare),
+		})
+	}
+
+	tap.Table(
+		[]string{"Segment", "Customers", "Share", "Revenue", "Revenue share"},
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold, MaxWidth: 140})
+}
+
+func exportRFMScores(path string, scores []reporting.RFMScore) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := reporting.WriteRFMScoresCSV(out, scores); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
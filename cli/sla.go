@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/NimbleMarkets/ntcharts/barchart"
+	"github.com/yarlson/tap"
+	"refurbed.com/hackathon/reporting"
+)
+
+func renderShippingSLA(dataset *reporting.OrderDataset, cfg reporting.SLAConfig, comparisons []reporting.Comparison) {
+	fmt.Println("Shipping SLA")
+	fmt.Println()
+
+	total := dataset.SLACompliance(cfg)
+	tap.Message(fmt.Sprintf("Delivered within %d business days (default): %s, %d pending, %d shipped late",
+		cfg.Default.DeliverDays, formatRateEstimate(total.Rate()), total.Pending, total.ShippedLate))
+
+	renderSLAComplianceTable("Country", dataset.SLAComplianceByCountry(cfg), nil)
+	renderSLAComplianceTable("Category", dataset.SLAComplianceByCategory(cfg), nil)
+
+	_, latest := dataset.DateRange()
+	latest = time.Date(latest.Year(), latest.Month(), latest.Day(), 0, 0, 0, 0, time.UTC)
+	byWeek := dataset.SLAComplianceByWeek(cfg, latest.AddDate(0, 0, -(7*7)-1), latest.AddDate(0, 0, -1), comparisons...)
+
+	renderSLAComplianceTable("Week", byWeek, comparisons)
+	renderSLAComplianceGraph(byWeek)
+	printUnreliableNote()
+}
+
+// renderSLAComplianceTable compares the compliance rate of every row with the
+// given comparisons.
+func renderSLAComplianceTable(group string, data []reporting.SLACompliance, comparisons []reporting.Comparison) {
+	textData := make([][]string, 0)
+	for _, c := range data {
+		row := []string{
+			c.Title,
+			formatRateEstimate(c.Rate()),
+			fmt.Sprintf("%d", c.Met),
+			fmt.Sprintf("%d", c.Breached),
+			fmt.Sprintf("%d", c.Pending),
+			fmt.Sprintf("%d", c.ShippedLate),
+		}
+		row = append(row, comparisonCells(c.Compared, formatPercent, formatSignedPoints)...)
+		textData = append(textData, greyRowIfUnreliable(row, c.Rate()))
+	}
+
+	tap.Table(
+		append([]string{group, "Compliance", "Met", "Breached", "Pending", "Shipped late"}, comparisonHeaders(comparisons)...),
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold, MaxWidth: 140})
+}
+
+func renderSLAComplianceGraph(data []reporting.SLACompliance) {
+	values := make([]barchart.BarData, 0)
+	for _, c := range data {
+		values = append(
+			values,
+			barchart.BarData{
+				Label:  c.Start.Format("01-02"),
+				Values: []barchart.BarValue{{Name: "Compliance", Value: c.Rate().Rate(), Style: blockStyle}}})
+		values = append(values, comparisonBars(c.Compared)...)
+	}
+
+	bc := barchart.New(
+		140, 15,
+		barchart.WithDataSet(values),
+		barchart.WithMaxValue(1))
+
+	bc.Draw()
+
+	fmt.Println(bc.View())
+}
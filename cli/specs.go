@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NimbleMarkets/ntcharts/barchart"
+	"github.com/yarlson/tap"
+	"refurbed.com/hackathon/reporting"
+)
+
+func renderSpecBreakdown(dataset *reporting.OrderDataset) {
+	keys := make([]tap.SelectOption[string], 0)
+	for _, k := range dataset.AllSpecKeys() {
+		keys = append(keys, tap.SelectOption[string]{Value: k, Label: capitalize(k)})
+	}
+	if len(keys) == 0 {
+		tap.Message("The dataset has no item specs")
+		return
+	}
+	key := tap.Select(context.Background(), tap.SelectOptions[string]{
+		Message: "Select the spec:",
+		Options: keys,
+	})
+
+	categories := []tap.SelectOption[reporting.Category]{{Value: "", Label: "All categories"}}
+	for _, c := range dataset.AllCategories() {
+		categories = append(categories, tap.SelectOption[reporting.Category]{Value: c, Label: string(c)})
+	}
+	category := tap.Select(context.Background(), tap.SelectOptions[reporting.Category]{
+		Message: "Within category:",
+		Options: categories,
+	})
+
+	clearScreen()
+	title := "all categories"
+	if category != "" {
+		title = string(category)
+	}
+	fmt.Printf("Items by %s (%s)\n", key, title)
+	fmt.Println()
+
+	data := dataset.SpecBreakdown(key, category)
+	currency := dataset.ReportingCurrency()
+	segments := make([]string, 0)
+	estimates := map[string]reporting.RateEstimate{}
+	textData := make([][]string, 0)
+	for _, s := range data {
+		e := s.ReturnRateEstimate()
+		segments = append(segments, s.Value)
+		estimates[s.Value] = e
+		textData = append(textData, greyRowIfUnreliable([]string{
+			s.Value,
+			fmt.Sprintf("%d", s.Items),
+			formatRateEstimate(e),
+			formatMoney(currency, s.AveragePrice().InexactFloat64()),
+		}, e))
+	}
+
+	tap.Table(
+		[]string{capitalize(key), "Items", "Return rate", "Average price"},
+		textData,
+		tap.TableOptions{ShowBorders: true, HeaderStyle: tap.TableStyleBold, MaxWidth: 140})
+	printUnreliableNote()
+
+	renderSpecReturnRateGraph(data)
+	selectSegmentComparison(segments, estimates)
+}
+
+func renderSpecReturnRateGraph(data []reporting.SpecStats) {
+	values := make([]barchart.BarData, 0)
+	for _, s := range data {
+		values = append(
+			values,
+			barchart.BarData{
+				Label:  s.Value,
+				Values: []barchart.BarValue{{Name: "Return rate", Value: s.ReturnRate(), Style: blockStyle}}})
+	}
+
+	bc := barchart.New(
+		140, 15,
+		barchart.WithDataSet(values))
+
+	bc.Draw()
+
+	fmt.Println(bc.View())
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yarlson/tap"
+	"refurbed.com/hackathon/reporting"
+)
+
+const significanceLevel = 0.05
+
+var unreliableStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+
+func formatRateEstimate(e reporting.RateEstimate) string {
+	if e.Trials == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f%% [%.1f–%.1f%%] n=%d", 100*e.Rate(), 100*e.Lower, 100*e.Upper, e.Trials)
+}
+
+func greyIfUnreliable(text string, e reporting.RateEstimate) string {
+	if e.Reliable(reporting.DefaultMinSampleSize) {
+		return text
+	}
+	return unreliableStyle.Render(text)
+}
+
+// greyRowIfUnreliable greys out every cell of a table row whose rate rests on
+// too few samples.
+func greyRowIfUnreliable(row []string, e reporting.RateEstimate) []string {
+	for i := range row {
+		row[i] = greyIfUnreliable(row[i], e)
+	}
+	return row
+}
+
+func printUnreliableNote() {
+	fmt.Println(unreliableStyle.Render(fmt.Sprintf("Grey rows rest on fewer than %d items.", reporting.DefaultMinSampleSize)))
+}
+
+// selectSegmentComparison lets the user pick two segments and prints whether
+// their rates differ significantly.
+func selectSegmentComparison(segments []string, estimates map[string]reporting.RateEstimate) {
+	compare := tap.Confirm(context.Background(), tap.ConfirmOptions{
+		Message:  "Compare two segments?",
+		Active:   "Yes",
+		Inactive: "No",
+	})
+	if !compare {
+		return
+	}
+
+	options := make([]tap.SelectOption[string], 0)
+	for _, s := range segments {
+		options = append(options, tap.SelectOption[string]{Value: s, Label: s, Hint: formatRateEstimate(estimates[s])})
+	}
+	a := tap.Select(context.Background(), tap.SelectOptions[string]{Message: "First segment:", Options: options})
+	b := tap.Select(context.Background(), tap.SelectOptions[string]{Message: "Second segment:", Options: options})
+
+	test := reporting.TwoProportionZTest(estimates[a], estimates[b])
+	verdict := "not significant"
+	if test.Significant(significanceLevel) {
+		verdict = "significant"
+	}
+	fmt.Printf("%s: %s\n", a, formatRateEstimate(test.A))
+	fmt.Printf("%s: %s\n", b, formatRateEstimate(test.B))
+	fmt.Printf("Difference: %+.2f pp, z = %.2f, p = %.4f (%s at %.0f%%)\n",
+		100*test.Difference, test.Z, test.PValue, verdict, 100*significanceLevel)
+}
@@ -0,0 +1,246 @@
+package reporting
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+)
+
+type KPI int
+
+const (
+	KPIRevenue KPI = iota
+	KPIOrderCount
+	KPIReturnRate
+	KPIMedianDelivery
+	KPIMedianDeliveryBusinessDays
+)
+
+func (k KPI) String() string {
+	switch k {
+	case KPIRevenue:
+		return "revenue"
+	case KPIOrderCount:
+		return "order count"
+	case KPIReturnRate:
+		return "return rate"
+	case KPIMedianDelivery:
+		return "median delivery hours"
+	case KPIMedianDeliveryBusinessDays:
+		return "median delivery business days"
+	default:
+		return "UNKNOWN KPI"
+	}
+}
+
+func AllKPIs() []KPI {
+	return []KPI{KPIRevenue, KPIOrderCount, KPIReturnRate, KPIMedianDelivery, KPIMedianDeliveryBusinessDays}
+}
+
+type DailyValue struct {
+	Day   time.Time
+	Value float64
+	// Valid is false on days without data for the KPI, for example the median
+	// delivery of a day whose items were not delivered yet.
+	Valid bool
+}
+
+type dailyAggregate struct {
+	revenue    float64
+	orders     map[OrderID]struct{}
+	items      int
+	returned   int
+	deliveries []time.Duration
+	// businessDays are the delivery times in business days.
+	businessDays []float64
+}
+
+func (a *dailyAggregate) value(kpi KPI) (float64, bool) {
+	switch kpi {
+	case KPIRevenue:
+		return a.revenue, true
+	case KPIOrderCount:
+		return float64(len(a.orders)), true
+	case KPIReturnRate:
+		if a.items == 0 {
+			return 0, false
+		}
+		return float64(a.returned) / float64(a.items), true
+	case KPIMedianDelivery:
+		if len(a.deliveries) == 0 {
+			return 0, false
+		}
+		slices.Sort(a.deliveries)
+		return medianDuration(a.deliveries).Hours(), true
+	case KPIMedianDeliveryBusinessDays:
+		if len(a.businessDays) == 0 {
+			return 0, false
+		}
+		slices.Sort(a.businessDays)
+		return Percentile(a.businessDays, 50), true
+	default:
+		return 0, false
+	}
+}
+
+func medianDuration(sorted []time.Duration) time.Duration {
+	l := len(sorted)
+	if l%2 == 0 {
+		return (sorted[l/2-1] + sorted[l/2]) / 2
+	}
+	return sorted[l/2]
+}
+
+// DailyKPISeries returns one value per day between start and end, both
+// truncated to whole days, grouped by the day items were ordered.
+func (ds *OrderDataset) DailyKPISeries(start, end time.Time) map[KPI][]DailyValue {
+	const day = 24 * time.Hour
+	startTrunc := start.Truncate(day)
+	endTrunc := end.Truncate(day)
+
+	aggregates := map[time.Time]*dailyAggregate{}
+	for date := startTrunc; !date.After(endTrunc); date = date.Add(day) {
+		aggregates[date] = &dailyAggregate{orders: map[OrderID]struct{}{}}
+	}
+	for item := range ds.recognizedItems() {
+		date := item.OrderedAt.Truncate(day)
+		a := aggregates[date]
+		if a == nil {
+			continue
+		}
+		a.revenue += item.Revenue().InexactFloat64()
+		a.orders[item.OrderID] = struct{}{}
+		a.items++
+		if !item.Refunded.IsZero() {
+			a.returned++
+		}
+		if !item.DeliveredAt.IsZero() {
+			a.deliveries = append(a.deliveries, item.DeliveredIn())
+			a.businessDays = append(a.businessDays, float64(item.businessDaysToDelivery))
+		}
+	}
+
+	res := map[KPI][]DailyValue{}
+	for date := startTrunc; !date.After(endTrunc); date = date.Add(day) {
+		for _, kpi := range AllKPIs() {
+			v, ok := aggregates[date].value(kpi)
+			res[kpi] = append(res[kpi], DailyValue{Day: date, Value: v, Valid: ok})
+		}
+	}
+	return res
+}
+
+type AnomalyConfig struct {
+	// Weeks is how many previous same weekdays form the baseline of a day.
+	Weeks int
+	// MinHistory is the number of valid baseline days needed to score a day.
+	MinHistory int
+	// Threshold is the absolute robust z-score above which a day is flagged.
+	Threshold float64
+	// MinRelativeSpread is the smallest spread, relative to the baseline
+	// median, a day is scored against when the baseline does not vary at all.
+	MinRelativeSpread float64
+}
+
+func DefaultAnomalyConfig() AnomalyConfig {
+	return AnomalyConfig{
+		Weeks:             8,
+		MinHistory:        4,
+		Threshold:         3.5,
+		MinRelativeSpread: 0.05,
+	}
+}
+
+type Anomaly struct {
+	KPI      KPI
+	Day      time.Time
+	Value    float64
+	Expected float64
+	// Score is the robust z-score, positive when Value is above Expected.
+	Score float64
+}
+
+// DetectAnomalies scores every day against the same weekday of the previous
+// weeks using the median and the median absolute deviation, so a few broken
+// days in the baseline do not hide new ones. When more than half the baseline
+// equals the median the mean absolute deviation stands in for the MAD, and a
+// flat baseline is scored against MinRelativeSpread of its median. Only days
+// over a baseline of zeros are never flagged. The series must hold
+// consecutive days.
+func DetectAnomalies(kpi KPI, series []DailyValue, cfg AnomalyConfig) []Anomaly {
+	var res []Anomaly
+	for i, d := range series {
+		if !d.Valid {
+			continue
+		}
+		baseline := make([]float64, 0, cfg.Weeks)
+		for w := 1; w <= cfg.Weeks; w++ {
+			j := i - 7*w
+			if j < 0 {
+				break
+			}
+			if series[j].Valid {
+				baseline = append(baseline, series[j].Value)
+			}
+		}
+		if len(baseline) < max(cfg.MinHistory, 1) {
+			continue
+		}
+		median := medianFloat(baseline)
+		deviations := make([]float64, len(baseline))
+		meanDeviation := 0.0
+		for k, v := range baseline {
+			deviations[k] = math.Abs(v - median)
+			meanDeviation += deviations[k] / float64(len(baseline))
+		}
+		// Scale the deviations to estimate the standard deviation of normally
+		// distributed values.
+		spread := 1.4826 * medianFloat(deviations)
+		if spread == 0 {
+			spread = 1.2533 * meanDeviation
+		}
+		if spread == 0 {
+			spread = cfg.MinRelativeSpread * math.Abs(median)
+		}
+		if spread == 0 {
+			continue
+		}
+		score := (d.Value - median) / spread
+		if math.Abs(score) > cfg.Threshold {
+			res = append(res, Anomaly{KPI: kpi, Day: d.Day, Value: d.Value, Expected: median, Score: score})
+		}
+	}
+	return res
+}
+
+func medianFloat(values []float64) float64 {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	l := len(sorted)
+	if l%2 == 0 {
+		return (sorted[l/2-1] + sorted[l/2]) / 2
+	}
+	return sorted[l/2]
+}
+
+// DailyAnomalies flags days between start and end on every KPI. The baseline
+// reaches back before start as far as the config needs.
+func (ds *OrderDataset) DailyAnomalies(start, end time.Time, cfg AnomalyConfig) []Anomaly {
+	const day = 24 * time.Hour
+	startTrunc := start.Truncate(day)
+	series := ds.DailyKPISeries(startTrunc.AddDate(0, 0, -7*cfg.Weeks), end)
+
+	var res []Anomaly
+	for _, kpi := range AllKPIs() {
+		for _, a := range DetectAnomalies(kpi, series[kpi], cfg) {
+			if !a.Day.Before(startTrunc) {
+				res = append(res, a)
+			}
+		}
+	}
+	slices.SortFunc(res, func(a, b Anomaly) int {
+		return cmp.Or(a.Day.Compare(b.Day), cmp.Compare(a.KPI, b.KPI))
+	})
+	return res
+}
@@ -0,0 +1,54 @@
+package reporting_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"refurbed.com/hackathon/reporting"
+)
+
+func TestDetectAnomalies(t *testing.T) {
+	start := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	weekly := []float64{100, 110, 105, 120, 130, 60, 40}
+
+	var series []reporting.DailyValue
+	for i := range 10 * 7 {
+		v := weekly[i%7] + float64(i%3)
+		series = append(series, reporting.DailyValue{Day: start.AddDate(0, 0, i), Value: v, Valid: true})
+	}
+	// A low Saturday is normal, a Tuesday at the same level is not.
+	series[64].Value = 60
+
+	anomalies := reporting.DetectAnomalies(reporting.KPIRevenue, series, reporting.DefaultAnomalyConfig())
+	require.Len(t, anomalies, 1)
+	require.Equal(t, start.AddDate(0, 0, 64), anomalies[0].Day)
+	require.Equal(t, time.Tuesday, anomalies[0].Day.Weekday())
+	require.Less(t, anomalies[0].Score, -3.5)
+	require.InDelta(t, 111, anomalies[0].Expected, 1)
+}
+
+func TestDetectAnomaliesOnFlatBaseline(t *testing.T) {
+	start := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	returnRates := make([]reporting.DailyValue, 9*7)
+	orderCounts := make([]reporting.DailyValue, 9*7)
+	for i := range returnRates {
+		day := start.AddDate(0, 0, i)
+		returnRates[i] = reporting.DailyValue{Day: day, Valid: true}
+		orderCounts[i] = reporting.DailyValue{Day: day, Value: 20, Valid: true}
+	}
+	// One earlier return on a Monday keeps the baseline mostly zero.
+	returnRates[7].Value = 0.05
+	returnRates[56].Value = 0.4
+	orderCounts[57].Value = 10
+
+	anomalies := reporting.DetectAnomalies(reporting.KPIReturnRate, returnRates, reporting.DefaultAnomalyConfig())
+	require.Len(t, anomalies, 1)
+	require.Equal(t, start.AddDate(0, 0, 56), anomalies[0].Day)
+
+	anomalies = reporting.DetectAnomalies(reporting.KPIOrderCount, orderCounts, reporting.DefaultAnomalyConfig())
+	require.Len(t, anomalies, 1)
+	require.Equal(t, start.AddDate(0, 0, 57), anomalies[0].Day)
+	require.Less(t, anomalies[0].Score, -3.5)
+}
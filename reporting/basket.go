@@ -0,0 +1,131 @@
+package reporting
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/RoaringBitmap/roaring"
+)
+
+// AssociationRule reads as "orders containing Antecedent also contain
+// Consequent". Support is the share of all orders containing both, Confidence
+// the share of Antecedent orders containing Consequent, and Lift how much more
+// likely Consequent is given Antecedent than in any order.
+type AssociationRule struct {
+	Antecedent string
+	Consequent string
+	Orders     int
+	Support    float64
+	Confidence float64
+	Lift       float64
+}
+
+type itemPair struct {
+	a, b string
+}
+
+// CategoryAssociationRules mines rules between categories bought in the same
+// order. Pairs where one category is an ancestor of the other are skipped as
+// they co-occur by definition.
+func (ds *OrderDataset) CategoryAssociationRules(minSupport float64) []AssociationRule {
+	numOrders := len(ds.orders)
+	if numOrders == 0 {
+		return nil
+	}
+	frequent := make([]Category, 0, len(ds.categories))
+	counts := map[string]int{}
+	for _, cat := range ds.AllCategories() {
+		n := int(ds.features.orderCategory[cat].GetCardinality())
+		if float64(n)/float64(numOrders) >= minSupport {
+			frequent = append(frequent, cat)
+			counts[string(cat)] = n
+		}
+	}
+
+	pairCounts := map[itemPair]int{}
+	for i, a := range frequent {
+		for _, b := range frequent[i+1:] {
+			if ds.isCategoryAncestor(a, b) || ds.isCategoryAncestor(b, a) {
+				continue
+			}
+			n := int(roaring.And(ds.features.orderCategory[a], ds.features.orderCategory[b]).GetCardinality())
+			if n > 0 {
+				pairCounts[itemPair{string(a), string(b)}] = n
+			}
+		}
+	}
+	return associationRules(counts, pairCounts, numOrders, minSupport)
+}
+
+// ItemAssociationRules mines rules between item names bought in the same
+// order.
+func (ds *OrderDataset) ItemAssociationRules(minSupport float64) []AssociationRule {
+	numOrders := len(ds.orders)
+	if numOrders == 0 {
+		return nil
+	}
+	counts := map[string]int{}
+	for order := range ds.AllOrders() {
+		for _, name := range orderItemNames(order) {
+			counts[name]++
+		}
+	}
+	for name, n := range counts {
+		if float64(n)/float64(numOrders) < minSupport {
+			delete(counts, name)
+		}
+	}
+
+	pairCounts := map[itemPair]int{}
+	for order := range ds.AllOrders() {
+		names := slices.DeleteFunc(orderItemNames(order), func(name string) bool {
+			_, ok := counts[name]
+			return !ok
+		})
+		for i, a := range names {
+			for _, b := range names[i+1:] {
+				pairCounts[itemPair{a, b}]++
+			}
+		}
+	}
+	return associationRules(counts, pairCounts, numOrders, minSupport)
+}
+
+func orderItemNames(order Order) []string {
+	names := make([]string, 0, len(order))
+	for _, item := range order {
+		names = append(names, item.ItemName)
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+func associationRules(counts map[string]int, pairCounts map[itemPair]int, numOrders int, minSupport float64) []AssociationRule {
+	var rules []AssociationRule
+	for pair, n := range pairCounts {
+		support := float64(n) / float64(numOrders)
+		if support < minSupport {
+			continue
+		}
+		for _, dir := range []itemPair{pair, {pair.b, pair.a}} {
+			confidence := float64(n) / float64(counts[dir.a])
+			consequentSupport := float64(counts[dir.b]) / float64(numOrders)
+			rules = append(rules, AssociationRule{
+				Antecedent: dir.a,
+				Consequent: dir.b,
+				Orders:     n,
+				Support:    support,
+				Confidence: confidence,
+				Lift:       confidence / consequentSupport,
+			})
+		}
+	}
+	slices.SortFunc(rules, func(x, y AssociationRule) int {
+		return cmp.Or(
+			cmp.Compare(y.Lift, x.Lift),
+			cmp.Compare(y.Support, x.Support),
+			cmp.Compare(x.Antecedent, y.Antecedent),
+			cmp.Compare(x.Consequent, y.Consequent))
+	})
+	return rules
+}
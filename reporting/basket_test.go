@@ -0,0 +1,41 @@
+package reporting_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategoryAssociationRules(t *testing.T) {
+	dataset := importTestDataset(t,
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,iPhone 13,,400.00,40.00,0,paid,DE,,,Phones>Smartphones",
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,Case,,20.00,2.00,0,paid,DE,,,Accessories",
+		"ORD-2,2025-01-10T10:00:00Z,b@example.com,iPhone 12,,300.00,30.00,0,paid,DE,,,Phones>Smartphones",
+		"ORD-2,2025-01-10T10:00:00Z,b@example.com,Case,,20.00,2.00,0,paid,DE,,,Accessories",
+		"ORD-3,2025-01-10T10:00:00Z,c@example.com,iPhone 11,,200.00,20.00,0,paid,DE,,,Phones>Smartphones",
+		"ORD-4,2025-01-10T10:00:00Z,d@example.com,MacBook,,900.00,90.00,0,paid,DE,,,Laptops",
+	)
+
+	rules := dataset.CategoryAssociationRules(0.1)
+	for _, r := range rules {
+		require.NotEqual(t, [2]string{"Phones", "Smartphones"}, [2]string{r.Antecedent, r.Consequent})
+	}
+
+	var accessoriesToPhones bool
+	for _, r := range rules {
+		if r.Antecedent == "Accessories" && r.Consequent == "Phones" {
+			accessoriesToPhones = true
+			require.Equal(t, 2, r.Orders)
+			require.InDelta(t, 0.5, r.Support, 1e-9)
+			require.InDelta(t, 1.0, r.Confidence, 1e-9)
+			require.InDelta(t, 4.0/3, r.Lift, 1e-9)
+		}
+	}
+	require.True(t, accessoriesToPhones)
+
+	require.Empty(t, dataset.CategoryAssociationRules(0.6))
+
+	items := dataset.ItemAssociationRules(0.25)
+	require.Len(t, items, 4)
+	require.Equal(t, "Case", items[0].Antecedent)
+}
@@ -0,0 +1,136 @@
+package reporting
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// HolidayCalendar holds public holidays per country. Saturdays and Sundays are
+// never business days. A nil calendar knows no holidays.
+type HolidayCalendar struct {
+	holidays map[string]map[time.Time]string
+}
+
+func NewHolidayCalendar() *HolidayCalendar {
+	return &HolidayCalendar{holidays: map[string]map[time.Time]string{}}
+}
+
+// LoadHolidaysFromCSV reads a table with the columns country, date and name.
+func LoadHolidaysFromCSV(r io.Reader) (*HolidayCalendar, error) {
+	cal := NewHolidayCalendar()
+	return cal, cal.AddFromCSV(r, "")
+}
+
+// AddFromCSV adds the holidays of a table. When country is set the table does
+// not need a country column, as in a file holding the holidays of one country.
+func (c *HolidayCalendar) AddFromCSV(r io.Reader, country string) error {
+	csvr := csv.NewReader(r)
+	csvr.FieldsPerRecord = -1
+
+	headerFields, err := csvr.Read()
+	if err != nil {
+		return fmt.Errorf("unexpected I/O error before CSV header: %w", err)
+	}
+	countryIdx := slices.Index(headerFields, "country")
+	dateIdx := slices.Index(headerFields, "date")
+	nameIdx := slices.Index(headerFields, "name")
+	if (country == "" && countryIdx == -1) || dateIdx == -1 {
+		return fmt.Errorf("holidays header %q must contain country and date", headerFields)
+	}
+
+	for {
+		fields, err := csvr.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("read CSV row: %w", err)
+		}
+		date, err := time.Parse(time.DateOnly, fields[dateIdx])
+		if err != nil {
+			return fmt.Errorf("parse holiday date: %w", err)
+		}
+		rowCountry, name := country, ""
+		if countryIdx != -1 && countryIdx < len(fields) {
+			rowCountry = fields[countryIdx]
+		}
+		if nameIdx != -1 && nameIdx < len(fields) {
+			name = fields[nameIdx]
+		}
+		c.Add(rowCountry, date, name)
+	}
+}
+
+func (c *HolidayCalendar) Add(country string, date time.Time, name string) {
+	country = strings.ToUpper(country)
+	if c.holidays[country] == nil {
+		c.holidays[country] = map[time.Time]string{}
+	}
+	c.holidays[country][dayOf(date)] = name
+}
+
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (c *HolidayCalendar) Holiday(country string, date time.Time) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	name, ok := c.holidays[strings.ToUpper(country)][dayOf(date)]
+	return name, ok
+}
+
+// HolidaysOn returns the holidays on a day in any country as "DE: name",
+// sorted by country.
+func (c *HolidayCalendar) HolidaysOn(date time.Time) []string {
+	if c == nil {
+		return nil
+	}
+	var res []string
+	for _, country := range slices.Sorted(maps.Keys(c.holidays)) {
+		if name, ok := c.holidays[country][dayOf(date)]; ok {
+			res = append(res, fmt.Sprintf("%s: %s", country, name))
+		}
+	}
+	return res
+}
+
+func (c *HolidayCalendar) IsBusinessDay(country string, date time.Time) bool {
+	if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		return false
+	}
+	_, holiday := c.Holiday(country, date)
+	return !holiday
+}
+
+// BusinessDaysBetween counts the business days after the day of from up to
+// and including the day of to, so an order placed on Friday and delivered on
+// Monday took one business day.
+func (c *HolidayCalendar) BusinessDaysBetween(country string, from, to time.Time) int {
+	n := 0
+	for day := dayOf(from).AddDate(0, 0, 1); !day.After(dayOf(to)); day = day.AddDate(0, 0, 1) {
+		if c.IsBusinessDay(country, day) {
+			n++
+		}
+	}
+	return n
+}
+
+// AddBusinessDays returns the day n business days after the day of from.
+func (c *HolidayCalendar) AddBusinessDays(country string, from time.Time, n int) time.Time {
+	day := dayOf(from)
+	for n > 0 {
+		day = day.AddDate(0, 0, 1)
+		if c.IsBusinessDay(country, day) {
+			n--
+		}
+	}
+	return day
+}
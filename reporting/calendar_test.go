@@ -0,0 +1,61 @@
+package reporting_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+
+	"refurbed.com/hackathon/reporting"
+)
+
+func TestDeliveredInBusinessDays(t *testing.T) {
+	cal := reporting.NewHolidayCalendar()
+	require.NoError(t, cal.AddFromCSV(strings.NewReader("date,name\n2025-01-13,Test holiday\n"), "DE"))
+	require.Equal(t, []string{"DE: Test holiday"}, cal.HolidaysOn(time.Date(2025, 1, 13, 12, 0, 0, 0, time.UTC)))
+
+	in := strings.NewReader(testCSVHeader + "\n" + strings.Join([]string{
+		"ORD-1,2025-01-10T18:00:00Z,a@example.com,iPhone 13,,400.00,40.00,0,paid,DE,2025-01-13T10:00:00Z,2025-01-14T10:00:00Z,Phones",
+		"ORD-2,2025-01-10T18:00:00Z,b@example.com,iPhone 13,,400.00,40.00,0,paid,FR,2025-01-13T10:00:00Z,2025-01-14T10:00:00Z,Phones",
+		"ORD-3,2025-01-10T18:00:00Z,c@example.com,iPhone 12,,300.00,30.00,0,paid,FR,2025-01-13T10:00:00Z,2025-01-15T10:00:00Z,Phones",
+		"ORD-4,2025-01-14T10:00:00Z,d@example.com,MacBook Air,,900.00,90.00,0,paid,DE,,,Laptops",
+	}, "\n") + "\n")
+	dataset, err := reporting.ImportOrderDatasetFromCSV(in, reporting.WithHolidayCalendar(cal))
+	require.NoError(t, err)
+
+	var days []int
+	for order := range dataset.AllOrders() {
+		days = append(days, order.DeliveredInBusinessDays(dataset.Calendar()))
+	}
+	require.ElementsMatch(t, []int{1, 2, 3, 0}, days)
+	require.Equal(t, 2.0, dataset.MedianDeliveryBusinessDays())
+	require.InDelta(t, 2.9, dataset.DeliveryBusinessDaysPercentile(95), 1e-9)
+	require.Equal(t, 109*time.Hour+36*time.Minute, dataset.DeliveryPercentile(95))
+
+	registry, err := reporting.NewMetricRegistry([]reporting.MetricDefinition{
+		{Name: "delivery_days", Expression: "delivery_business_days", Aggregation: reporting.AggregationAvg},
+		{Name: "slow", Expression: "delivery_hours > 24", Aggregation: reporting.AggregationSum},
+	})
+	require.NoError(t, err)
+	deliveryDays, _ := registry.Lookup("delivery_days")
+	rows := dataset.Query(reporting.MetricQuery{Metric: deliveryDays})
+	require.Len(t, rows, 1)
+	require.True(t, decimal.NewFromInt(2).Equal(rows[0].Value))
+	require.Equal(t, int64(3), rows[0].Count)
+	slow, _ := registry.Lookup("slow")
+	require.True(t, decimal.NewFromInt(3).Equal(dataset.Evaluate(slow)))
+}
+
+func TestMedianDeliverySkipsUndeliveredItems(t *testing.T) {
+	dataset := importTestDataset(t,
+		"ORD-1,2025-01-06T10:00:00Z,a@example.com,iPhone 13,,400.00,40.00,0,paid,DE,2025-01-07T10:00:00Z,2025-01-08T10:00:00Z,Phones",
+		"ORD-2,2025-01-06T10:00:00Z,b@example.com,iPhone 13,,400.00,40.00,0,paid,DE,,,Phones",
+	)
+
+	// Counting the undelivered item as zero would halve the median to 24h.
+	require.Equal(t, 48*time.Hour, dataset.MedianDelivery())
+	require.Equal(t, 48*time.Hour, dataset.DeliveryPercentile(50))
+	require.Equal(t, 2.0, dataset.MedianDeliveryBusinessDays())
+}
@@ -0,0 +1,75 @@
+package reporting
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Cohort groups the customers whose first order was placed in Month. Active
+// and Revenue are indexed by the number of months since Month, up to the last
+// month of the dataset.
+type Cohort struct {
+	Month     time.Time
+	Customers int
+	Active    []int
+	Revenue   []decimal.Decimal
+}
+
+// Retention is the share of the cohort that ordered in the given month offset.
+func (c Cohort) Retention(offset int) float64 {
+	if c.Customers == 0 || offset >= len(c.Active) {
+		return 0
+	}
+	return float64(c.Active[offset]) / float64(c.Customers)
+}
+
+func monthOf(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func monthsBetween(from, to time.Time) int {
+	return (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+}
+
+func (ds *OrderDataset) MonthlyCohorts() []Cohort {
+	lastMonth := monthOf(ds.latestOrderedAt)
+	cohorts := map[time.Time]*Cohort{}
+
+	for c := range ds.Customers() {
+		if len(c.Orders) == 0 {
+			continue
+		}
+		month := monthOf(c.FirstOrderedAt())
+		cohort := cohorts[month]
+		if cohort == nil {
+			numMonths := monthsBetween(month, lastMonth) + 1
+			cohort = &Cohort{
+				Month:   month,
+				Active:  make([]int, numMonths),
+				Revenue: make([]decimal.Decimal, numMonths),
+			}
+			cohorts[month] = cohort
+		}
+		cohort.Customers++
+
+		lastOffset := -1
+		for _, order := range c.Orders {
+			offset := monthsBetween(month, monthOf(order.OrderedAt()))
+			if offset != lastOffset {
+				cohort.Active[offset]++
+				lastOffset = offset
+			}
+			cohort.Revenue[offset] = cohort.Revenue[offset].Add(order.AfterRefunds())
+		}
+	}
+
+	res := make([]Cohort, 0, len(cohorts))
+	for _, month := range slices.SortedFunc(maps.Keys(cohorts), time.Time.Compare) {
+		res = append(res, *cohorts[month])
+	}
+	return res
+}
@@ -0,0 +1,33 @@
+package reporting_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonthlyCohorts(t *testing.T) {
+	dataset := importTestDataset(t,
+		"ORD-1,2025-01-06T10:00:00Z,a@example.com,iPhone 13,,400.00,40.00,0,paid,DE,,,Phones",
+		"ORD-2,2025-01-20T10:00:00Z,b@example.com,iPhone 12,,300.00,30.00,0,paid,DE,,,Phones",
+		"ORD-3,2025-03-02T10:00:00Z,a@example.com,Case,,20.00,2.00,0,paid,DE,,,Accessories",
+		"ORD-4,2025-03-05T10:00:00Z,a@example.com,Charger,,30.00,3.00,0,paid,DE,,,Accessories",
+		"ORD-5,2025-02-10T10:00:00Z,c@example.com,iPhone 11,,200.00,20.00,0,paid,AT,,,Phones",
+	)
+
+	cohorts := dataset.MonthlyCohorts()
+	require.Len(t, cohorts, 2)
+
+	jan := cohorts[0]
+	require.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), jan.Month)
+	require.Equal(t, 2, jan.Customers)
+	require.Equal(t, []int{2, 0, 1}, jan.Active)
+	require.InDelta(t, 0.5, jan.Retention(2), 1e-9)
+	require.True(t, decimal.NewFromInt(50).Equal(jan.Revenue[2]))
+
+	feb := cohorts[1]
+	require.Equal(t, 1, feb.Customers)
+	require.Equal(t, []int{1, 0}, feb.Active)
+}
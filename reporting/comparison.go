@@ -0,0 +1,89 @@
+package reporting
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Comparison int
+
+const (
+	ComparisonPreviousPeriod Comparison = iota
+	ComparisonPreviousYear
+)
+
+func (c Comparison) String() string {
+	switch c {
+	case ComparisonPreviousPeriod:
+		return "previous period"
+	case ComparisonPreviousYear:
+		return "previous year"
+	default:
+		return "UNKNOWN COMPARISON"
+	}
+}
+
+// previousYearShift is 52 weeks rather than a calendar year so that compared
+// days fall on the same weekday and weekly intervals stay aligned.
+const previousYearShift = 52 * 7 * 24 * time.Hour
+
+// shift is how far back the compared window lies for a window of numIntervals
+// intervals.
+func (c Comparison) shift(numIntervals int, interval time.Duration) time.Duration {
+	if c == ComparisonPreviousYear {
+		return previousYearShift
+	}
+	return time.Duration(numIntervals) * interval
+}
+
+// ComparedValue is the headline value of an interval in the compared window,
+// for example the revenue of the same day last year.
+type ComparedValue struct {
+	Comparison Comparison
+	Start      time.Time
+	End        time.Time
+	Value      decimal.Decimal
+	Delta      decimal.Decimal
+}
+
+// DeltaPercent is the change relative to the compared value. It is not
+// defined when the compared value is zero.
+func (c ComparedValue) DeltaPercent() (float64, bool) {
+	if c.Value.IsZero() {
+		return 0, false
+	}
+	return c.Delta.Div(c.Value.Abs()).Mul(decimal.NewFromInt(100)).InexactFloat64(), true
+}
+
+// seriesInterval is an interval of a time series report that can be compared
+// with earlier periods by its headline value.
+type seriesInterval interface {
+	bounds() (start, end time.Time)
+	headline() decimal.Decimal
+}
+
+// compareSeries computes the series of the window shifted back by each
+// comparison and returns the compared values per interval of current.
+func compareSeries[T seriesInterval](current []T, start, end time.Time, interval time.Duration, comparisons []Comparison, series func(start, end time.Time) []T) [][]ComparedValue {
+	res := make([][]ComparedValue, len(current))
+	for _, comparison := range comparisons {
+		shift := comparison.shift(len(current), interval)
+		compared := series(start.Add(-shift), end.Add(-shift))
+		for i := range current {
+			if i >= len(compared) {
+				break
+			}
+			from, to := compared[i].bounds()
+			value := compared[i].headline()
+			res[i] = append(res[i], ComparedValue{
+				Comparison: comparison,
+				Start:      from,
+				End:        to,
+				Value:      value,
+				Delta:      current[i].headline().Sub(value),
+			})
+		}
+	}
+	return res
+}
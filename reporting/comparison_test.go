@@ -0,0 +1,73 @@
+package reporting_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+
+	"refurbed.com/hackathon/reporting"
+)
+
+func TestRevenueByDayComparisons(t *testing.T) {
+	dataset := importTestDataset(t,
+		"ORD-1,2024-01-16T10:00:00Z,a@example.com,iPhone 13,,50.00,5.00,0,paid,DE,,,Phones",
+		"ORD-2,2025-01-12T10:00:00Z,b@example.com,iPhone 12,,200.00,20.00,0,paid,DE,,,Phones",
+		"ORD-3,2025-01-14T10:00:00Z,c@example.com,iPhone 11,,100.00,10.00,0,paid,DE,,,Phones",
+		"ORD-4,2025-01-14T11:00:00Z,d@example.com,iPhone 11,,150.00,15.00,0,paid,DE,,,Phones",
+	)
+
+	start := time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	byDay := dataset.RevenueByDayFor(reporting.RevenueBasisGross, start, end,
+		reporting.ComparisonPreviousPeriod, reporting.ComparisonPreviousYear)
+	require.Len(t, byDay, 2)
+
+	first := byDay[0]
+	require.Len(t, first.Compared, 2)
+
+	previousPeriod := first.Compared[0]
+	require.Equal(t, time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC), previousPeriod.Start)
+	require.True(t, decimal.NewFromInt(200).Equal(previousPeriod.Value))
+	require.True(t, decimal.NewFromInt(50).Equal(previousPeriod.Delta))
+	pct, ok := previousPeriod.DeltaPercent()
+	require.True(t, ok)
+	require.InDelta(t, 25.0, pct, 1e-9)
+
+	previousYear := first.Compared[1]
+	require.Equal(t, time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC), previousYear.Start)
+	require.True(t, decimal.NewFromInt(50).Equal(previousYear.Value))
+
+	_, ok = byDay[1].Compared[1].DeltaPercent()
+	require.False(t, ok)
+}
+
+func TestWeeklySeriesComparisons(t *testing.T) {
+	dataset := importTestDataset(t,
+		"ORD-1,2025-01-06T10:00:00Z,a@example.com,iPhone 13,,400.00,40.00,0,paid,DE,,,Phones",
+		"ORD-2,2025-01-07T10:00:00Z,b@example.com,iPhone 12,,300.00,30.00,0,paid,DE,,,Phones",
+		"ORD-3,2025-01-13T10:00:00Z,a@example.com,iPhone 11,,100.00,10.00,0,paid,DE,,,Phones",
+	)
+
+	start := time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)
+
+	earnings := dataset.EarningsByWeek(start, end, reporting.ComparisonPreviousPeriod)
+	require.Len(t, earnings, 1)
+	require.Len(t, earnings[0].Compared, 1)
+	require.Equal(t, time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), earnings[0].Compared[0].Start)
+	require.True(t, decimal.NewFromInt(70).Equal(earnings[0].Compared[0].Value))
+	require.True(t, decimal.NewFromInt(-60).Equal(earnings[0].Compared[0].Delta))
+
+	customers := dataset.CustomersByWeek(start, end, reporting.ComparisonPreviousPeriod)
+	require.Len(t, customers, 1)
+	require.True(t, decimal.NewFromInt(2).Equal(customers[0].Compared[0].Value))
+	require.True(t, decimal.NewFromInt(-1).Equal(customers[0].Compared[0].Delta))
+
+	statuses := dataset.PaymentStatusByWeek(start, end, reporting.ComparisonPreviousPeriod)
+	require.Len(t, statuses, 1)
+	require.True(t, decimal.NewFromInt(2).Equal(statuses[0].Compared[0].Value))
+
+	require.Empty(t, dataset.EarningsByWeek(start, end)[0].Compared)
+}
@@ -0,0 +1,192 @@
+package reporting
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Currency string
+
+const DefaultCurrency Currency = "EUR"
+
+func (c Currency) Symbol() string {
+	switch c {
+	case "EUR":
+		return "€"
+	case "PLN":
+		return "zł"
+	case "SEK", "DKK":
+		return "kr"
+	case "CHF":
+		return "CHF"
+	case "GBP":
+		return "£"
+	case "USD":
+		return "$"
+	default:
+		return string(c)
+	}
+}
+
+// OriginalAmounts holds the money fields of an order item as they were
+// imported, before conversion to the reporting currency.
+type OriginalAmounts struct {
+	Currency   Currency
+	ItemPrice  decimal.Decimal
+	Commission decimal.Decimal
+	Refunded   decimal.Decimal
+}
+
+type fxRate struct {
+	EffectiveFrom time.Time
+	Rate          decimal.Decimal
+}
+
+// FXRates is a date-effective exchange rate table. Every rate is expressed as
+// units of the currency per one unit of DefaultCurrency, and applies from its
+// date until the next rate of the same currency.
+type FXRates struct {
+	rates map[Currency][]fxRate
+}
+
+func LoadFXRatesFromCSV(r io.Reader) (*FXRates, error) {
+	csvr := csv.NewReader(r)
+
+	headerFields, err := csvr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unexpected I/O error before CSV header: %w", err)
+	}
+	dateIdx := slices.Index(headerFields, "date")
+	currencyIdx := slices.Index(headerFields, "currency")
+	rateIdx := slices.Index(headerFields, "rate")
+	if dateIdx == -1 || currencyIdx == -1 || rateIdx == -1 {
+		return nil, fmt.Errorf("FX rates header %q must contain date, currency and rate", headerFields)
+	}
+
+	fx := &FXRates{rates: map[Currency][]fxRate{}}
+	for {
+		fields, err := csvr.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("read CSV row: %w", err)
+		}
+		effectiveFrom, err := time.Parse(time.DateOnly, fields[dateIdx])
+		if err != nil {
+			return nil, fmt.Errorf("parse FX rate date: %w", err)
+		}
+		rate, err := decimal.NewFromString(fields[rateIdx])
+		if err != nil {
+			return nil, fmt.Errorf("parse FX rate: %w", err)
+		}
+		if !rate.IsPositive() {
+			return nil, fmt.Errorf("FX rate for %s on %s must be positive", fields[currencyIdx], fields[dateIdx])
+		}
+		fx.Add(Currency(strings.ToUpper(fields[currencyIdx])), effectiveFrom, rate)
+	}
+	return fx, nil
+}
+
+func (fx *FXRates) Add(currency Currency, effectiveFrom time.Time, rate decimal.Decimal) {
+	if fx.rates == nil {
+		fx.rates = map[Currency][]fxRate{}
+	}
+	rates := append(fx.rates[currency], fxRate{EffectiveFrom: effectiveFrom, Rate: rate})
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].EffectiveFrom.Before(rates[j].EffectiveFrom)
+	})
+	fx.rates[currency] = rates
+}
+
+func (fx *FXRates) Currencies() []Currency {
+	all := []Currency{DefaultCurrency}
+	if fx == nil {
+		return all
+	}
+	for currency := range fx.rates {
+		if currency != DefaultCurrency {
+			all = append(all, currency)
+		}
+	}
+	slices.Sort(all[1:])
+	return all
+}
+
+func (fx *FXRates) rateAt(currency Currency, at time.Time) (decimal.Decimal, error) {
+	if currency == DefaultCurrency {
+		return decimal.NewFromInt(1), nil
+	}
+	if fx == nil {
+		return decimal.Zero, fmt.Errorf("no FX rates loaded for %s", currency)
+	}
+	rates := fx.rates[currency]
+	idx := sort.Search(len(rates), func(i int) bool {
+		return rates[i].EffectiveFrom.After(at)
+	})
+	if idx == 0 {
+		return decimal.Zero, fmt.Errorf("no FX rate for %s effective at %s", currency, at.Format(time.DateOnly))
+	}
+	return rates[idx-1].Rate, nil
+}
+
+func (fx *FXRates) Convert(amount decimal.Decimal, from, to Currency, at time.Time) (decimal.Decimal, error) {
+	if from == to {
+		return amount, nil
+	}
+	fromRate, err := fx.rateAt(from, at)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	toRate, err := fx.rateAt(to, at)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return amount.Div(fromRate).Mul(toRate).Round(2), nil
+}
+
+func convertOrderItem(item OrderItem, to Currency, fx *FXRates) (OrderItem, error) {
+	orig := item.Original
+	price, err := fx.Convert(orig.ItemPrice, orig.Currency, to, item.OrderedAt)
+	if err != nil {
+		return OrderItem{}, fmt.Errorf("convert item_price of %s: %w", item.OrderID, err)
+	}
+	commission, err := fx.Convert(orig.Commission, orig.Currency, to, item.OrderedAt)
+	if err != nil {
+		return OrderItem{}, fmt.Errorf("convert commission of %s: %w", item.OrderID, err)
+	}
+	refunded, err := fx.Convert(orig.Refunded, orig.Currency, to, item.OrderedAt)
+	if err != nil {
+		return OrderItem{}, fmt.Errorf("convert refunded of %s: %w", item.OrderID, err)
+	}
+	item.Currency = to
+	item.ItemPrice = price
+	item.Commission = commission
+	item.Refunded = refunded
+	return item, nil
+}
+
+func (ds *OrderDataset) ReportingCurrency() Currency {
+	if ds.reportingCurrency == "" {
+		return DefaultCurrency
+	}
+	return ds.reportingCurrency
+}
+
+// InCurrency returns a copy of the dataset with all money metrics computed in
+// the given reporting currency. Original amounts are kept on every item.
+func (ds *OrderDataset) InCurrency(currency Currency, fx *FXRates) (*OrderDataset, error) {
+	return ds.derive(func(derived *OrderDataset) {
+		derived.reportingCurrency = currency
+	}, func(item OrderItem) (OrderItem, error) {
+		return convertOrderItem(item, currency, fx)
+	})
+}
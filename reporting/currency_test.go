@@ -0,0 +1,50 @@
+package reporting_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+
+	"refurbed.com/hackathon/reporting"
+)
+
+const testFXRates = `date,currency,rate
+2025-01-01,PLN,4.00
+2025-01-15,PLN,5.00
+2025-01-01,SEK,10.00
+`
+
+func TestImportWithReportingCurrency(t *testing.T) {
+	fx, err := reporting.LoadFXRatesFromCSV(strings.NewReader(testFXRates))
+	require.NoError(t, err)
+
+	in := testCSVHeader + ",currency\n" +
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,iPhone 13,,400.00,40.00,0,paid,PL,,,Phones,PLN\n" +
+		"ORD-2,2025-01-20T10:00:00Z,b@example.com,iPhone 13,,500.00,50.00,100.00,paid,PL,,,Phones,PLN\n" +
+		"ORD-3,2025-01-20T10:00:00Z,c@example.com,iPhone 12,,100.00,10.00,0,paid,DE,,,Phones,\n"
+
+	dataset, err := reporting.ImportOrderDatasetFromCSV(strings.NewReader(in), reporting.WithReportingCurrency(reporting.DefaultCurrency, fx))
+	require.NoError(t, err)
+	require.Equal(t, reporting.DefaultCurrency, dataset.ReportingCurrency())
+	require.True(t, decimal.NewFromInt(280).Equal(dataset.TotalRevenue()), dataset.TotalRevenue().String())
+
+	items := slices.Collect(dataset.AllItems())
+	require.Equal(t, reporting.Currency("PLN"), items[0].Original.Currency)
+	require.True(t, decimal.NewFromInt(400).Equal(items[0].Original.ItemPrice))
+
+	inSEK, err := dataset.InCurrency("SEK", fx)
+	require.NoError(t, err)
+	require.Equal(t, reporting.Currency("SEK"), inSEK.ReportingCurrency())
+	require.True(t, decimal.NewFromInt(2800).Equal(inSEK.TotalRevenue()), inSEK.TotalRevenue().String())
+}
+
+func TestImportFailsWithoutFXRate(t *testing.T) {
+	in := testCSVHeader + ",currency\n" +
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,iPhone 13,,400.00,40.00,0,paid,PL,,,Phones,PLN\n"
+
+	_, err := reporting.ImportOrderDatasetFromCSV(strings.NewReader(in))
+	require.Error(t, err)
+}
@@ -0,0 +1,202 @@
+package reporting
+
+import (
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Customer struct {
+	Email  string
+	Orders []Order
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func (c Customer) NumOrders() int {
+	return len(c.Orders)
+}
+
+func (c Customer) FirstOrderedAt() time.Time {
+	if len(c.Orders) == 0 {
+		return time.Time{}
+	}
+	return c.Orders[0].OrderedAt()
+}
+
+func (c Customer) LastOrderedAt() time.Time {
+	if len(c.Orders) == 0 {
+		return time.Time{}
+	}
+	return c.Orders[len(c.Orders)-1].OrderedAt()
+}
+
+// LifetimeValue is the value of all orders of the customer after refunds.
+func (c Customer) LifetimeValue() decimal.Decimal {
+	total := decimal.Zero
+	for _, order := range c.Orders {
+		total = total.Add(order.AfterRefunds())
+	}
+	return total
+}
+
+func (ds *OrderDataset) addCustomerOrder(item OrderItem) {
+	if ds.customerOrders == nil {
+		ds.customerOrders = map[string][]OrderID{}
+	}
+	email := normalizeEmail(item.CustomerEmail)
+	if email == "" {
+		return
+	}
+	ds.customerOrders[email] = append(ds.customerOrders[email], item.OrderID)
+}
+
+func (ds *OrderDataset) customer(email string) Customer {
+	ids := ds.customerOrders[email]
+	c := Customer{
+		Email:  email,
+		Orders: make([]Order, 0, len(ids)),
+	}
+	for _, id := range ids {
+		var order Order
+		for _, item := range ds.orders[id] {
+			if ds.recognizes(item) {
+				order = append(order, item)
+			}
+		}
+		c.Orders = append(c.Orders, order)
+	}
+	slices.SortFunc(c.Orders, func(a, b Order) int {
+		return a.OrderedAt().Compare(b.OrderedAt())
+	})
+	return c
+}
+
+func (ds *OrderDataset) Customer(email string) (Customer, bool) {
+	email = normalizeEmail(email)
+	if _, ok := ds.customerOrders[email]; !ok {
+		return Customer{}, false
+	}
+	return ds.customer(email), true
+}
+
+// Customers iterates all customers ordered by email.
+func (ds *OrderDataset) Customers() iter.Seq[Customer] {
+	emails := slices.Sorted(maps.Keys(ds.customerOrders))
+	return func(yield func(Customer) bool) {
+		for _, email := range emails {
+			if !yield(ds.customer(email)) {
+				return
+			}
+		}
+	}
+}
+
+func (ds *OrderDataset) NumCustomers() int {
+	return len(ds.customerOrders)
+}
+
+// RepeatPurchaseRate is the share of customers with more than one order.
+func (ds *OrderDataset) RepeatPurchaseRate() float64 {
+	return ds.RepeatPurchaseRateEstimate().Rate()
+}
+
+func (ds *OrderDataset) RepeatPurchaseRateEstimate() RateEstimate {
+	repeat := 0
+	for _, ids := range ds.customerOrders {
+		if len(ids) > 1 {
+			repeat++
+		}
+	}
+	return NewRateEstimate(repeat, len(ds.customerOrders))
+}
+
+type OrdersPerCustomer struct {
+	NumOrders    int
+	NumCustomers int
+}
+
+func (ds *OrderDataset) OrdersPerCustomerDistribution() []OrdersPerCustomer {
+	counts := map[int]int{}
+	for _, ids := range ds.customerOrders {
+		counts[len(ids)]++
+	}
+	res := make([]OrdersPerCustomer, 0, len(counts))
+	for _, numOrders := range slices.Sorted(maps.Keys(counts)) {
+		res = append(res, OrdersPerCustomer{NumOrders: numOrders, NumCustomers: counts[numOrders]})
+	}
+	return res
+}
+
+func (ds *OrderDataset) AverageCustomerLifetimeValue() decimal.Decimal {
+	if len(ds.customerOrders) == 0 {
+		return decimal.Zero
+	}
+	total := decimal.Zero
+	for c := range ds.Customers() {
+		total = total.Add(c.LifetimeValue())
+	}
+	return total.Div(decimal.NewFromInt(int64(len(ds.customerOrders))))
+}
+
+type PeriodCustomers struct {
+	Start     time.Time
+	End       time.Time
+	Title     string
+	New       int
+	Returning int
+	// Compared holds the total customers of the compared periods.
+	Compared []ComparedValue
+}
+
+func (p PeriodCustomers) Total() int {
+	return p.New + p.Returning
+}
+
+func (p PeriodCustomers) bounds() (time.Time, time.Time) { return p.Start, p.End }
+
+func (p PeriodCustomers) headline() decimal.Decimal { return decimal.NewFromInt(int64(p.Total())) }
+
+func (ds *OrderDataset) CustomersByWeek(start, end time.Time, comparisons ...Comparison) []PeriodCustomers {
+	return ds.customersByTimeInterval(start, end, 7*24*time.Hour, weekTitle, comparisons)
+}
+
+// customersByTimeInterval counts every customer once per interval they
+// ordered in, as new when it holds their first order and as returning
+// otherwise.
+func (ds *OrderDataset) customersByTimeInterval(start, end time.Time, interval time.Duration, titleFn func(from, to time.Time) string, comparisons []Comparison) []PeriodCustomers {
+	buckets := newIntervalBuckets(start, end, interval, func(from, to time.Time) PeriodCustomers {
+		return PeriodCustomers{Start: from, End: to, Title: titleFn(from, to)}
+	})
+	for c := range ds.Customers() {
+		first := buckets.at(c.FirstOrderedAt())
+		seen := map[*PeriodCustomers]struct{}{}
+		for _, order := range c.Orders {
+			p := buckets.at(order.OrderedAt())
+			if _, ok := seen[p]; ok || p == nil {
+				continue
+			}
+			seen[p] = struct{}{}
+			if p == first {
+				p.New++
+			} else {
+				p.Returning++
+			}
+		}
+	}
+	res := buckets.buckets
+
+	compared := compareSeries(res, start, end, interval, comparisons, func(start, end time.Time) []PeriodCustomers {
+		return ds.customersByTimeInterval(start, end, interval, titleFn, nil)
+	})
+	for i := range res {
+		res[i].Compared = compared[i]
+	}
+	return res
+}
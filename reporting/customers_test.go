@@ -0,0 +1,42 @@
+package reporting_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+
+	"refurbed.com/hackathon/reporting"
+)
+
+func TestCustomers(t *testing.T) {
+	dataset := importTestDataset(t,
+		"ORD-1,2025-01-06T10:00:00Z,a@example.com,iPhone 13,,400.00,40.00,0,paid,DE,,,Phones",
+		"ORD-1,2025-01-06T10:00:00Z,a@example.com,Case,,100.00,10.00,0,paid,DE,,,Accessories",
+		"ORD-2,2025-01-14T10:00:00Z,A@Example.com,iPhone 12,,300.00,30.00,100.00,paid,DE,,,Phones",
+		"ORD-3,2025-01-15T10:00:00Z,b@example.com,iPhone 11,,200.00,20.00,0,paid,AT,,,Phones",
+	)
+
+	require.Equal(t, 2, dataset.NumCustomers())
+	require.InDelta(t, 0.5, dataset.RepeatPurchaseRate(), 1e-9)
+	require.Equal(t, []reporting.OrdersPerCustomer{
+		{NumOrders: 1, NumCustomers: 1},
+		{NumOrders: 2, NumCustomers: 1},
+	}, dataset.OrdersPerCustomerDistribution())
+
+	customer, ok := dataset.Customer("a@example.com")
+	require.True(t, ok)
+	require.Equal(t, 2, customer.NumOrders())
+	require.True(t, decimal.NewFromInt(700).Equal(customer.LifetimeValue()))
+	require.True(t, decimal.NewFromInt(450).Equal(dataset.AverageCustomerLifetimeValue()))
+
+	weeks := dataset.CustomersByWeek(
+		time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC))
+	require.Len(t, weeks, 2)
+	require.Equal(t, 1, weeks[0].New)
+	require.Equal(t, 0, weeks[0].Returning)
+	require.Equal(t, 1, weeks[1].New)
+	require.Equal(t, 1, weeks[1].Returning)
+}
@@ -0,0 +1,145 @@
+package reporting
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// NetCommission is the commission kept after refunds. A refund returns the
+// same share of the commission as it returns of the item price.
+func (r OrderItem) NetCommission() decimal.Decimal {
+	if r.Refunded.IsZero() || r.ItemPrice.IsZero() {
+		return r.Commission
+	}
+	if r.Refunded.GreaterThanOrEqual(r.ItemPrice) {
+		return decimal.Zero
+	}
+	kept := r.ItemPrice.Sub(r.Refunded).Div(r.ItemPrice)
+	return r.Commission.Mul(kept).Round(2)
+}
+
+type Earnings struct {
+	Title         string
+	Start         time.Time
+	End           time.Time
+	GMV           decimal.Decimal
+	Commission    decimal.Decimal
+	NetCommission decimal.Decimal
+	// Compared holds the net commission of the compared periods of a time
+	// series.
+	Compared []ComparedValue
+}
+
+func (e Earnings) bounds() (time.Time, time.Time) { return e.Start, e.End }
+
+func (e Earnings) headline() decimal.Decimal { return e.NetCommission }
+
+func (e Earnings) TakeRate() float64 {
+	if e.GMV.IsZero() {
+		return 0
+	}
+	return e.Commission.Div(e.GMV).InexactFloat64()
+}
+
+func (e Earnings) NetTakeRate() float64 {
+	if e.GMV.IsZero() {
+		return 0
+	}
+	return e.NetCommission.Div(e.GMV).InexactFloat64()
+}
+
+func (e *Earnings) add(item OrderItem) {
+	e.GMV = e.GMV.Add(item.ItemPrice)
+	e.Commission = e.Commission.Add(item.Commission)
+	e.NetCommission = e.NetCommission.Add(item.NetCommission())
+}
+
+func (ds *OrderDataset) TotalCommission() decimal.Decimal {
+	return ds.totalCommission
+}
+
+func (ds *OrderDataset) TotalNetCommission() decimal.Decimal {
+	return ds.totalNetCommission
+}
+
+// TakeRate is the total commission divided by the gross merchandise value.
+func (ds *OrderDataset) TakeRate() float64 {
+	return ds.TotalEarnings().TakeRate()
+}
+
+func (ds *OrderDataset) TotalEarnings() Earnings {
+	return Earnings{
+		Title:         "Total",
+		Start:         ds.earliestOrderedAt,
+		End:           ds.latestOrderedAt,
+		GMV:           ds.totalGross,
+		Commission:    ds.totalCommission,
+		NetCommission: ds.totalNetCommission,
+	}
+}
+
+func (ds *OrderDataset) EarningsByCategory() []Earnings {
+	res := make([]Earnings, 0, len(ds.categories))
+	for _, cat := range ds.AllCategories() {
+		e := Earnings{Title: string(cat)}
+		ds.features.orderItemCategory[cat].Iterate(func(itemID uint32) bool {
+			if item := ds.allItems[itemID]; ds.recognizes(item) {
+				e.add(item)
+			}
+			return true
+		})
+		res = append(res, e)
+	}
+	return res
+}
+
+func (ds *OrderDataset) EarningsByCountry() []Earnings {
+	byCountry := map[string]*Earnings{}
+	for item := range ds.AllItems() {
+		if !ds.recognizes(item) {
+			continue
+		}
+		e := byCountry[item.Country]
+		if e == nil {
+			e = &Earnings{Title: item.Country}
+			byCountry[item.Country] = e
+		}
+		e.add(item)
+	}
+	res := make([]Earnings, 0, len(byCountry))
+	for _, country := range slices.Sorted(maps.Keys(byCountry)) {
+		res = append(res, *byCountry[country])
+	}
+	return res
+}
+
+func (ds *OrderDataset) EarningsByDay(start, end time.Time, comparisons ...Comparison) []Earnings {
+	return ds.earningsByTimeInterval(start, end, 24*time.Hour, dayTitle, comparisons)
+}
+
+func (ds *OrderDataset) EarningsByWeek(start, end time.Time, comparisons ...Comparison) []Earnings {
+	return ds.earningsByTimeInterval(start, end, 7*24*time.Hour, weekTitle, comparisons)
+}
+
+func (ds *OrderDataset) earningsByTimeInterval(start, end time.Time, interval time.Duration, titleFn func(from, to time.Time) string, comparisons []Comparison) []Earnings {
+	buckets := newIntervalBuckets(start, end, interval, func(from, to time.Time) Earnings {
+		return Earnings{Title: titleFn(from, to), Start: from, End: to}
+	})
+	for item := range ds.recognizedItems() {
+		if e := buckets.at(item.OrderedAt); e != nil {
+			e.add(item)
+		}
+	}
+	res := buckets.buckets
+
+	compared := compareSeries(res, start, end, interval, comparisons, func(start, end time.Time) []Earnings {
+		return ds.earningsByTimeInterval(start, end, interval, titleFn, nil)
+	})
+	for i := range res {
+		res[i].Compared = compared[i]
+	}
+	return res
+}
@@ -0,0 +1,32 @@
+package reporting_test
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEarnings(t *testing.T) {
+	dataset := importTestDataset(t,
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,iPhone 13,,400.00,40.00,0,paid,DE,,,Phones>Smartphones",
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,Case,,100.00,20.00,50.00,paid,DE,,,Accessories",
+		"ORD-2,2025-01-11T10:00:00Z,b@example.com,iPhone 12,,500.00,40.00,500.00,paid,AT,,,Phones>Smartphones",
+	)
+
+	require.True(t, decimal.NewFromInt(100).Equal(dataset.TotalCommission()))
+	require.True(t, decimal.NewFromInt(50).Equal(dataset.TotalNetCommission()))
+	require.InDelta(t, 0.1, dataset.TakeRate(), 1e-9)
+
+	byCategory := dataset.EarningsByCategory()
+	require.Len(t, byCategory, 3)
+	require.Equal(t, "Accessories", byCategory[0].Title)
+	require.True(t, decimal.NewFromInt(10).Equal(byCategory[0].NetCommission))
+	require.Equal(t, "Phones", byCategory[1].Title)
+	require.True(t, decimal.NewFromInt(900).Equal(byCategory[1].GMV))
+
+	byCountry := dataset.EarningsByCountry()
+	require.Len(t, byCountry, 2)
+	require.Equal(t, "AT", byCountry[0].Title)
+	require.True(t, byCountry[0].NetCommission.IsZero())
+}
@@ -0,0 +1,31 @@
+package reporting
+
+import (
+	"maps"
+	"slices"
+)
+
+// ItemFilter selects order items by country and category. Empty fields match
+// every item.
+type ItemFilter struct {
+	Country  string
+	Category Category
+}
+
+func (f ItemFilter) Matches(item OrderItem) bool {
+	if f.Country != "" && item.Country != f.Country {
+		return false
+	}
+	if f.Category != "" && !slices.Contains(item.Category, f.Category) {
+		return false
+	}
+	return true
+}
+
+func (ds *OrderDataset) AllCountries() []string {
+	countries := map[string]struct{}{}
+	for item := range ds.AllItems() {
+		countries[item.Country] = struct{}{}
+	}
+	return slices.Sorted(maps.Keys(countries))
+}
@@ -0,0 +1,183 @@
+package reporting
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var ErrNotEnoughHistory = errors.New("not enough history to forecast")
+
+// ForecastConfig configures additive Holt-Winters smoothing. Smoothing factors
+// left at zero are fitted to the history with a grid search. A SeasonLength of
+// one or less disables seasonality.
+type ForecastConfig struct {
+	Alpha        float64
+	Beta         float64
+	Gamma        float64
+	SeasonLength int
+	// Z is the number of standard deviations covered by the prediction
+	// interval, 1.96 for a 95% interval.
+	Z float64
+}
+
+type ForecastPoint struct {
+	Start    time.Time
+	End      time.Time
+	Title    string
+	Forecast decimal.Decimal
+	Lower    decimal.Decimal
+	Upper    decimal.Decimal
+}
+
+type holtWintersModel struct {
+	level    float64
+	trend    float64
+	seasonal []float64
+	sse      float64
+	n        int
+}
+
+func fitHoltWinters(series []float64, seasonLength int, alpha, beta, gamma float64) holtWintersModel {
+	m := max(seasonLength, 1)
+	seasonal := make([]float64, m)
+
+	var level, trend float64
+	if m == 1 {
+		level = series[0]
+		trend = series[1] - series[0]
+	} else {
+		first, second := mean(series[:m]), mean(series[m:2*m])
+		level = first
+		trend = (second - first) / float64(m)
+		for i := range m {
+			seasonal[i] = series[i] - first
+		}
+	}
+
+	model := holtWintersModel{seasonal: seasonal}
+	for t, x := range series {
+		s := seasonal[t%m]
+		if t >= m {
+			err := x - (level + trend + s)
+			model.sse += err * err
+			model.n++
+		}
+		newLevel := alpha*(x-s) + (1-alpha)*(level+trend)
+		trend = beta*(newLevel-level) + (1-beta)*trend
+		if m > 1 {
+			seasonal[t%m] = gamma*(x-newLevel) + (1-gamma)*s
+		}
+		level = newLevel
+	}
+	model.level = level
+	model.trend = trend
+	return model
+}
+
+func (m holtWintersModel) forecast(numObserved, h int) float64 {
+	return m.level + float64(h)*m.trend + m.seasonal[(numObserved+h-1)%len(m.seasonal)]
+}
+
+func (m holtWintersModel) sigma() float64 {
+	if m.n == 0 {
+		return 0
+	}
+	return math.Sqrt(m.sse / float64(m.n))
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total / float64(len(values))
+}
+
+var smoothingGrid = []float64{0.05, 0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9}
+
+func gridOrFixed(v float64) []float64 {
+	if v > 0 {
+		return []float64{v}
+	}
+	return smoothingGrid
+}
+
+// HoltWintersForecast forecasts horizon values after series. The prediction
+// interval widens with the square root of the horizon, which approximates the
+// growth of the forecast error for the smoothing factors used in practice.
+func HoltWintersForecast(series []float64, horizon int, cfg ForecastConfig) (forecast, lower, upper []float64, err error) {
+	minLen := max(2*cfg.SeasonLength, 3)
+	if len(series) < minLen {
+		return nil, nil, nil, fmt.Errorf("%w: need %d values, got %d", ErrNotEnoughHistory, minLen, len(series))
+	}
+
+	var best holtWintersModel
+	bestSSE := math.Inf(1)
+	for _, alpha := range gridOrFixed(cfg.Alpha) {
+		for _, beta := range gridOrFixed(cfg.Beta) {
+			gammas := gridOrFixed(cfg.Gamma)
+			if cfg.SeasonLength <= 1 {
+				gammas = gammas[:1]
+			}
+			for _, gamma := range gammas {
+				model := fitHoltWinters(series, cfg.SeasonLength, alpha, beta, gamma)
+				if model.sse < bestSSE {
+					best, bestSSE = model, model.sse
+				}
+			}
+		}
+	}
+
+	sigma := best.sigma()
+	for h := 1; h <= horizon; h++ {
+		f := best.forecast(len(series), h)
+		spread := cfg.Z * sigma * math.Sqrt(float64(h))
+		forecast = append(forecast, f)
+		lower = append(lower, f-spread)
+		upper = append(upper, f+spread)
+	}
+	return forecast, lower, upper, nil
+}
+
+func (ds *OrderDataset) ForecastRevenueByDay(basis RevenueBasis, start, end time.Time, horizon int) ([]ForecastPoint, error) {
+	history := ds.RevenueByDayFor(basis, start, end)
+	return forecastIntervals(history, 24*time.Hour, horizon, ForecastConfig{SeasonLength: 7, Z: 1.96}, dayTitle)
+}
+
+func (ds *OrderDataset) ForecastRevenueByWeek(basis RevenueBasis, start, end time.Time, horizon int) ([]ForecastPoint, error) {
+	history := ds.RevenueByWeekFor(basis, start, end)
+	return forecastIntervals(history, 7*24*time.Hour, horizon, ForecastConfig{Z: 1.96}, weekTitle)
+}
+
+func forecastIntervals(history []IntervalRevenue, interval time.Duration, horizon int, cfg ForecastConfig, titleFn func(from, to time.Time) string) ([]ForecastPoint, error) {
+	series := make([]float64, len(history))
+	for i, r := range history {
+		series[i] = r.Revenue.InexactFloat64()
+	}
+	forecast, lower, upper, err := HoltWintersForecast(series, horizon, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	next := history[len(history)-1].End
+	res := make([]ForecastPoint, horizon)
+	for i := range res {
+		res[i] = ForecastPoint{
+			Start:    next,
+			End:      next.Add(interval),
+			Title:    titleFn(next, next.Add(interval)),
+			Forecast: decimal.NewFromFloat(forecast[i]).Round(2),
+			Lower:    decimal.NewFromFloat(lower[i]).Round(2),
+			Upper:    decimal.NewFromFloat(upper[i]).Round(2),
+		}
+		next = next.Add(interval)
+	}
+	return res, nil
+}
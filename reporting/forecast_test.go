@@ -0,0 +1,31 @@
+package reporting_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"refurbed.com/hackathon/reporting"
+)
+
+func TestHoltWintersForecast(t *testing.T) {
+	weekly := []float64{100, 120, 110, 130, 150, 80, 60}
+	var series []float64
+	for week := range 8 {
+		for _, v := range weekly {
+			series = append(series, v+float64(week)*7)
+		}
+	}
+
+	forecast, lower, upper, err := reporting.HoltWintersForecast(series, 7, reporting.ForecastConfig{SeasonLength: 7, Z: 1.96})
+	require.NoError(t, err)
+	require.Len(t, forecast, 7)
+	for i, v := range weekly {
+		require.InDelta(t, v+8*7, forecast[i], 5)
+		require.LessOrEqual(t, lower[i], forecast[i])
+		require.GreaterOrEqual(t, upper[i], forecast[i])
+	}
+
+	_, _, _, err = reporting.HoltWintersForecast(series[:10], 7, reporting.ForecastConfig{SeasonLength: 7})
+	require.ErrorIs(t, err, reporting.ErrNotEnoughHistory)
+}
@@ -0,0 +1,214 @@
+package reporting
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type FraudRule int
+
+const (
+	FraudRuleRefundRatio FraudRule = iota
+	FraudRuleOrderBurst
+	FraudRuleRefundExceedsPrice
+	FraudRuleEmailDomain
+)
+
+func (r FraudRule) String() string {
+	switch r {
+	case FraudRuleRefundRatio:
+		return "high refund ratio"
+	case FraudRuleOrderBurst:
+		return "order burst"
+	case FraudRuleRefundExceedsPrice:
+		return "refund exceeds price"
+	case FraudRuleEmailDomain:
+		return "unusual email domain"
+	default:
+		return "UNKNOWN FRAUD RULE"
+	}
+}
+
+func AllFraudRules() []FraudRule {
+	return []FraudRule{FraudRuleRefundRatio, FraudRuleOrderBurst, FraudRuleRefundExceedsPrice, FraudRuleEmailDomain}
+}
+
+type FraudConfig struct {
+	// MinOrders and MaxRefundRatio flag customers with at least MinOrders
+	// orders of which more than MaxRefundRatio of the value was refunded.
+	MinOrders      int
+	MaxRefundRatio float64
+	// BurstOrders orders from one email within BurstWindow are a burst.
+	BurstOrders int
+	BurstWindow time.Duration
+	// DomainMinItems is the number of items an email domain needs before its
+	// return rate is compared to the other domains at DomainAlpha.
+	DomainMinItems int
+	DomainAlpha    float64
+	// Weights is the score a rule adds per signal. MinScore is the score from
+	// which a customer is flagged.
+	Weights  map[FraudRule]int
+	MinScore int
+}
+
+func DefaultFraudConfig() FraudConfig {
+	return FraudConfig{
+		MinOrders:      3,
+		MaxRefundRatio: 0.5,
+		BurstOrders:    3,
+		BurstWindow:    24 * time.Hour,
+		DomainMinItems: DefaultMinSampleSize,
+		DomainAlpha:    0.01,
+		Weights: map[FraudRule]int{
+			FraudRuleRefundRatio:        3,
+			FraudRuleOrderBurst:         2,
+			FraudRuleRefundExceedsPrice: 5,
+			FraudRuleEmailDomain:        1,
+		},
+		MinScore: 2,
+	}
+}
+
+type FraudSignal struct {
+	Rule   FraudRule
+	Reason string
+}
+
+type FlaggedCustomer struct {
+	Email       string
+	Orders      int
+	RefundRatio float64
+	Score       int
+	Signals     []FraudSignal
+}
+
+// DomainReturnRate compares the return rate of an email domain with the rate
+// of all other domains.
+type DomainReturnRate struct {
+	Domain string
+	Test   ProportionTest
+}
+
+type FraudReport struct {
+	// Customers are sorted by score, highest first.
+	Customers []FlaggedCustomer
+	// Domains are the email domains returning significantly more than the
+	// rest, sorted by domain.
+	Domains []DomainReturnRate
+}
+
+func emailDomain(email string) string {
+	_, domain, found := strings.Cut(email, "@")
+	if !found {
+		return ""
+	}
+	return domain
+}
+
+// UnusualEmailDomains returns the domains whose item return rate is
+// significantly higher than that of all other domains together.
+func (ds *OrderDataset) UnusualEmailDomains(cfg FraudConfig) []DomainReturnRate {
+	returned, items := map[string]int{}, map[string]int{}
+	totalReturned, totalItems := 0, 0
+	for item := range ds.AllItems() {
+		domain := emailDomain(normalizeEmail(item.CustomerEmail))
+		if domain == "" {
+			continue
+		}
+		items[domain]++
+		totalItems++
+		if !item.Refunded.IsZero() {
+			returned[domain]++
+			totalReturned++
+		}
+	}
+
+	var res []DomainReturnRate
+	for _, domain := range slices.Sorted(maps.Keys(items)) {
+		if items[domain] < cfg.DomainMinItems {
+			continue
+		}
+		test := TwoProportionZTest(
+			NewRateEstimate(returned[domain], items[domain]),
+			NewRateEstimate(totalReturned-returned[domain], totalItems-items[domain]))
+		if test.Difference > 0 && test.Significant(cfg.DomainAlpha) {
+			res = append(res, DomainReturnRate{Domain: domain, Test: test})
+		}
+	}
+	return res
+}
+
+// FraudReport scores every customer with the rules of the config and returns
+// those reaching the minimum score together with the reasons.
+func (ds *OrderDataset) FraudReport(cfg FraudConfig) FraudReport {
+	report := FraudReport{Domains: ds.UnusualEmailDomains(cfg)}
+	unusualDomains := map[string]DomainReturnRate{}
+	for _, d := range report.Domains {
+		unusualDomains[d.Domain] = d
+	}
+
+	for c := range ds.Customers() {
+		flagged := FlaggedCustomer{Email: c.Email, Orders: c.NumOrders()}
+
+		total, refunded := decimal.Zero, decimal.Zero
+		for _, order := range c.Orders {
+			for _, item := range order {
+				total = total.Add(item.ItemPrice)
+				refunded = refunded.Add(item.Refunded)
+				if item.Refunded.GreaterThan(item.ItemPrice) {
+					flagged.Signals = append(flagged.Signals, FraudSignal{
+						Rule:   FraudRuleRefundExceedsPrice,
+						Reason: fmt.Sprintf("%s refunded %s of %s for %s", item.OrderID, item.Refunded, item.ItemPrice, item.ItemName),
+					})
+				}
+			}
+		}
+		if total.IsPositive() {
+			flagged.RefundRatio = refunded.Div(total).InexactFloat64()
+		}
+		if flagged.Orders >= cfg.MinOrders && flagged.RefundRatio > cfg.MaxRefundRatio {
+			flagged.Signals = append(flagged.Signals, FraudSignal{
+				Rule:   FraudRuleRefundRatio,
+				Reason: fmt.Sprintf("%.0f%% of the value of %d orders refunded", 100*flagged.RefundRatio, flagged.Orders),
+			})
+		}
+
+		// Orders are sorted by time, so a burst is a window of BurstOrders
+		// consecutive orders.
+		for i := 0; cfg.BurstOrders > 1 && i+cfg.BurstOrders <= len(c.Orders); i++ {
+			first, last := c.Orders[i].OrderedAt(), c.Orders[i+cfg.BurstOrders-1].OrderedAt()
+			if last.Sub(first) <= cfg.BurstWindow {
+				flagged.Signals = append(flagged.Signals, FraudSignal{
+					Rule:   FraudRuleOrderBurst,
+					Reason: fmt.Sprintf("%d orders between %s and %s", cfg.BurstOrders, first.Format(time.DateTime), last.Format(time.DateTime)),
+				})
+				break
+			}
+		}
+
+		if d, ok := unusualDomains[emailDomain(c.Email)]; ok {
+			flagged.Signals = append(flagged.Signals, FraudSignal{
+				Rule:   FraudRuleEmailDomain,
+				Reason: fmt.Sprintf("%s returns %.1f%% of items vs %.1f%% elsewhere", d.Domain, 100*d.Test.A.Rate(), 100*d.Test.B.Rate()),
+			})
+		}
+
+		for _, s := range flagged.Signals {
+			flagged.Score += cfg.Weights[s.Rule]
+		}
+		if len(flagged.Signals) > 0 && flagged.Score >= cfg.MinScore {
+			report.Customers = append(report.Customers, flagged)
+		}
+	}
+
+	slices.SortStableFunc(report.Customers, func(a, b FlaggedCustomer) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	return report
+}
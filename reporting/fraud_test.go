@@ -0,0 +1,36 @@
+package reporting_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"refurbed.com/hackathon/reporting"
+)
+
+func TestFraudReport(t *testing.T) {
+	dataset := importTestDataset(t,
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,iPhone 13,,400.00,40.00,400.00,paid,DE,,,Phones",
+		"ORD-2,2025-01-10T12:00:00Z,a@example.com,iPhone 13,,400.00,40.00,400.00,paid,DE,,,Phones",
+		"ORD-3,2025-01-10T20:00:00Z,a@example.com,iPhone 12,,300.00,30.00,0,paid,DE,,,Phones",
+		"ORD-4,2025-01-11T10:00:00Z,b@example.com,Case,,20.00,2.00,25.00,paid,DE,,,Accessories",
+		"ORD-5,2025-01-12T10:00:00Z,c@example.com,Case,,20.00,2.00,0,paid,DE,,,Accessories",
+		"ORD-6,2025-01-20T10:00:00Z,c@example.com,Case,,20.00,2.00,0,paid,DE,,,Accessories",
+	)
+
+	report := dataset.FraudReport(reporting.DefaultFraudConfig())
+	require.Len(t, report.Customers, 2)
+
+	a := report.Customers[0]
+	require.Equal(t, "a@example.com", a.Email)
+	require.Equal(t, 5, a.Score)
+	require.InDelta(t, 800.0/1100.0, a.RefundRatio, 1e-9)
+	require.Equal(t, reporting.FraudRuleRefundRatio, a.Signals[0].Rule)
+	require.Equal(t, reporting.FraudRuleOrderBurst, a.Signals[1].Rule)
+
+	b := report.Customers[1]
+	require.Equal(t, "b@example.com", b.Email)
+	require.Len(t, b.Signals, 1)
+	require.Equal(t, reporting.FraudRuleRefundExceedsPrice, b.Signals[0].Rule)
+	require.Empty(t, report.Domains)
+}
@@ -0,0 +1,63 @@
+package reporting
+
+import (
+	"slices"
+
+	"github.com/RoaringBitmap/roaring"
+)
+
+func (ds *OrderDataset) TopLevelCategories() []Category {
+	var res []Category
+	for _, cat := range ds.AllCategories() {
+		if len(ds.categoryAncestors[cat]) == 0 {
+			res = append(res, cat)
+		}
+	}
+	return res
+}
+
+// GradeCrossTab holds item return rates per condition grade, in total and per
+// top-level category.
+type GradeCrossTab struct {
+	Grades     []string
+	Categories []Category
+	// ByGrade is indexed like Grades.
+	ByGrade []RateEstimate
+	// Cells is indexed by category, then grade.
+	Cells [][]RateEstimate
+}
+
+func (ds *OrderDataset) ReturnRateByGrade() GradeCrossTab {
+	var tab GradeCrossTab
+	gradeItems := map[string]*roaring.Bitmap{}
+	for sv, bitmap := range ds.features.spec {
+		if sv.Key != SpecKeyGrade {
+			continue
+		}
+		tab.Grades = append(tab.Grades, sv.Value)
+		gradeItems[sv.Value] = bitmap
+	}
+	slices.Sort(tab.Grades)
+
+	estimate := func(items *roaring.Bitmap) RateEstimate {
+		returned := roaring.And(items, ds.features.returned)
+		return NewRateEstimate(int(returned.GetCardinality()), int(items.GetCardinality()))
+	}
+	for _, grade := range tab.Grades {
+		tab.ByGrade = append(tab.ByGrade, estimate(gradeItems[grade]))
+	}
+	for _, cat := range ds.TopLevelCategories() {
+		row := make([]RateEstimate, len(tab.Grades))
+		graded := 0
+		for i, grade := range tab.Grades {
+			row[i] = estimate(roaring.And(gradeItems[grade], ds.features.orderItemCategory[cat]))
+			graded += row[i].Trials
+		}
+		if graded == 0 {
+			continue
+		}
+		tab.Categories = append(tab.Categories, cat)
+		tab.Cells = append(tab.Cells, row)
+	}
+	return tab
+}
@@ -0,0 +1,32 @@
+package reporting_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"refurbed.com/hackathon/reporting"
+)
+
+func TestReturnRateByGrade(t *testing.T) {
+	dataset := importTestDataset(t,
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,iPhone 13,grade=A,400.00,40.00,0,paid,DE,,,Phones>Smartphones",
+		"ORD-2,2025-01-11T10:00:00Z,b@example.com,iPhone 13,grade=C,300.00,40.00,300.00,paid,DE,,,Phones>Smartphones",
+		"ORD-3,2025-01-12T10:00:00Z,c@example.com,iPhone 12,grade=C,250.00,40.00,0,paid,DE,,,Phones>Smartphones",
+		"ORD-4,2025-01-12T10:00:00Z,c@example.com,MacBook Air,grade=A,900.00,40.00,900.00,paid,DE,,,Laptops",
+		"ORD-5,2025-01-12T10:00:00Z,c@example.com,Case,,20.00,2.00,0,paid,DE,,,Accessories",
+	)
+
+	tab := dataset.ReturnRateByGrade()
+	require.Equal(t, []string{"A", "C"}, tab.Grades)
+	require.Equal(t, []reporting.Category{"Laptops", "Phones"}, tab.Categories)
+	require.Equal(t, 2, tab.ByGrade[0].Trials)
+	require.InDelta(t, 0.5, tab.ByGrade[1].Rate(), 1e-9)
+
+	phonesC := tab.Cells[1][1]
+	require.Equal(t, 1, phonesC.Successes)
+	require.Equal(t, 2, phonesC.Trials)
+	require.Less(t, phonesC.Lower, 0.5)
+	require.Greater(t, phonesC.Upper, 0.5)
+	require.Zero(t, tab.Cells[0][1].Trials)
+}
@@ -0,0 +1,47 @@
+package reporting
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// WeekdayHourGrid aggregates orders by the weekday and hour they were placed.
+// Weekdays are indexed from Monday.
+type WeekdayHourGrid struct {
+	Location *time.Location
+	Orders   [7][24]int
+	Revenue  [7][24]decimal.Decimal
+}
+
+func mondayFirstWeekday(t time.Time) int {
+	return (int(t.Weekday()) + 6) % 7
+}
+
+// WeekdayHourHeatmap counts every order once, in the slot of its local order
+// time, if any of its items match the filter. Revenue only includes the
+// matching items with a recognized payment status.
+func (ds *OrderDataset) WeekdayHourHeatmap(loc *time.Location, filter ItemFilter) WeekdayHourGrid {
+	if loc == nil {
+		loc = time.UTC
+	}
+	grid := WeekdayHourGrid{Location: loc}
+	for order := range ds.AllOrders() {
+		matched := false
+		for _, item := range order {
+			if !filter.Matches(item) {
+				continue
+			}
+			local := item.OrderedAt.In(loc)
+			weekday, hour := mondayFirstWeekday(local), local.Hour()
+			if ds.recognizes(item) {
+				grid.Revenue[weekday][hour] = grid.Revenue[weekday][hour].Add(item.Revenue())
+			}
+			if !matched {
+				grid.Orders[weekday][hour]++
+				matched = true
+			}
+		}
+	}
+	return grid
+}
@@ -0,0 +1,35 @@
+package reporting_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+
+	"refurbed.com/hackathon/reporting"
+)
+
+func TestWeekdayHourHeatmap(t *testing.T) {
+	dataset := importTestDataset(t,
+		"ORD-1,2025-01-05T23:30:00Z,a@example.com,iPhone 13,,400.00,40.00,0,paid,AT,,,Phones",
+		"ORD-1,2025-01-05T23:30:00Z,a@example.com,Case,,20.00,2.00,0,paid,AT,,,Accessories",
+		"ORD-2,2025-01-06T08:15:00Z,b@example.com,iPhone 12,,300.00,30.00,0,paid,DE,,,Phones",
+		"ORD-3,2025-01-06T08:45:00Z,c@example.com,iPhone 11,,250.00,25.00,0,failed,DE,,,Phones",
+	)
+
+	vienna, err := time.LoadLocation("Europe/Vienna")
+	require.NoError(t, err)
+
+	grid := dataset.WeekdayHourHeatmap(vienna, reporting.ItemFilter{})
+	// Sunday 23:30 UTC is Monday 00:30 in Vienna.
+	require.Equal(t, 1, grid.Orders[0][0])
+	require.True(t, decimal.NewFromInt(420).Equal(grid.Revenue[0][0]))
+	require.Equal(t, 2, grid.Orders[0][9])
+	require.True(t, decimal.NewFromInt(300).Equal(grid.Revenue[0][9]))
+
+	utc := dataset.WeekdayHourHeatmap(time.UTC, reporting.ItemFilter{Country: "AT", Category: "Accessories"})
+	require.Equal(t, 1, utc.Orders[6][23])
+	require.True(t, decimal.NewFromInt(20).Equal(utc.Revenue[6][23]))
+	require.Zero(t, utc.Orders[0][8])
+}
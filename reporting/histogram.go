@@ -0,0 +1,142 @@
+package reporting
+
+import (
+	"math"
+	"slices"
+)
+
+// HistogramConfig configures the bins of a histogram. A zero BinWidth picks
+// the width with the Freedman-Diaconis rule. With LogScale the bins are
+// equally wide in log10 of the value and BinWidth is given in decades.
+type HistogramConfig struct {
+	BinWidth float64
+	LogScale bool
+	// MaxBins caps the number of bins an automatic or too small width yields.
+	MaxBins int
+}
+
+func DefaultHistogramConfig() HistogramConfig {
+	return HistogramConfig{MaxBins: 40}
+}
+
+type HistogramBin struct {
+	Lower float64
+	Upper float64
+	Count int
+}
+
+type Histogram struct {
+	Bins     []HistogramBin
+	LogScale bool
+}
+
+// NewHistogram bins the values. On a log scale values below one are counted
+// in the first bin.
+func NewHistogram(values []float64, cfg HistogramConfig) Histogram {
+	h := Histogram{LogScale: cfg.LogScale}
+	if len(values) == 0 {
+		return h
+	}
+
+	scaled := make([]float64, len(values))
+	for i, v := range values {
+		scaled[i] = v
+		if cfg.LogScale {
+			scaled[i] = math.Log10(max(v, 1))
+		}
+	}
+	slices.Sort(scaled)
+	lo, hi := scaled[0], scaled[len(scaled)-1]
+
+	width := cfg.BinWidth
+	if width <= 0 {
+		iqr := Percentile(scaled, 75) - Percentile(scaled, 25)
+		width = 2 * iqr / math.Cbrt(float64(len(scaled)))
+	}
+	if width <= 0 {
+		width = max(hi-lo, 1)
+	}
+	if !cfg.LogScale {
+		lo = math.Floor(lo/width) * width
+	}
+
+	numBins := max(int(math.Floor((hi-lo)/width))+1, 1)
+	if cfg.MaxBins > 0 && numBins > cfg.MaxBins {
+		// Widen the bins to end at hi, the last bin then includes it.
+		numBins = cfg.MaxBins
+		width = (hi - lo) / float64(numBins)
+	}
+	h.Bins = make([]HistogramBin, numBins)
+	for i := range h.Bins {
+		lower, upper := lo+float64(i)*width, lo+float64(i+1)*width
+		if cfg.LogScale {
+			lower, upper = math.Pow(10, lower), math.Pow(10, upper)
+		}
+		h.Bins[i] = HistogramBin{Lower: lower, Upper: upper}
+	}
+	for _, v := range scaled {
+		idx := min(int((v-lo)/width), numBins-1)
+		h.Bins[idx].Count++
+	}
+	return h
+}
+
+// Percentile interpolates linearly between the closest ranks of sorted values.
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := min(lower+1, len(sorted)-1)
+	return sorted[lower] + (rank-float64(lower))*(sorted[upper]-sorted[lower])
+}
+
+type PercentileValue struct {
+	Percentile float64
+	Value      float64
+}
+
+type OrderValueDistribution struct {
+	Histogram   Histogram
+	Orders      int
+	Median      float64
+	Percentiles []PercentileValue
+}
+
+var orderValuePercentiles = []float64{10, 25, 50, 75, 90, 95, 99}
+
+// OrderValues returns the value of every order with a recognized item matching
+// the filter, counting only those items.
+func (ds *OrderDataset) OrderValues(basis RevenueBasis, filter ItemFilter) []float64 {
+	var res []float64
+	for order := range ds.AllOrders() {
+		matched := false
+		value := 0.0
+		for _, item := range order {
+			if !ds.recognizes(item) || !filter.Matches(item) {
+				continue
+			}
+			matched = true
+			value += item.price(basis).InexactFloat64()
+		}
+		if matched {
+			res = append(res, value)
+		}
+	}
+	slices.Sort(res)
+	return res
+}
+
+func (ds *OrderDataset) OrderValueDistribution(basis RevenueBasis, filter ItemFilter, cfg HistogramConfig) OrderValueDistribution {
+	values := ds.OrderValues(basis, filter)
+	dist := OrderValueDistribution{
+		Histogram: NewHistogram(values, cfg),
+		Orders:    len(values),
+		Median:    Percentile(values, 50),
+	}
+	for _, p := range orderValuePercentiles {
+		dist.Percentiles = append(dist.Percentiles, PercentileValue{Percentile: p, Value: Percentile(values, p)})
+	}
+	return dist
+}
@@ -0,0 +1,50 @@
+package reporting_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"refurbed.com/hackathon/reporting"
+)
+
+func TestNewHistogram(t *testing.T) {
+	h := reporting.NewHistogram([]float64{5, 12, 18, 25, 99}, reporting.HistogramConfig{BinWidth: 10})
+	require.Len(t, h.Bins, 10)
+	require.Equal(t, reporting.HistogramBin{Lower: 0, Upper: 10, Count: 1}, h.Bins[0])
+	require.Equal(t, 2, h.Bins[1].Count)
+	require.Equal(t, 1, h.Bins[9].Count)
+
+	log := reporting.NewHistogram([]float64{1, 10, 100, 1000}, reporting.HistogramConfig{BinWidth: 1, LogScale: true})
+	require.Len(t, log.Bins, 4)
+	require.InDelta(t, 100, log.Bins[2].Lower, 1e-9)
+	require.Equal(t, 1, log.Bins[3].Count)
+
+	capped := reporting.NewHistogram([]float64{5, 12, 18, 25, 99}, reporting.HistogramConfig{BinWidth: 10, MaxBins: 4})
+	require.Len(t, capped.Bins, 4)
+	require.Zero(t, capped.Bins[0].Lower)
+	require.InDelta(t, 99, capped.Bins[3].Upper, 1e-9)
+	require.Equal(t, 1, capped.Bins[3].Count)
+
+	belowOne := reporting.NewHistogram([]float64{0, 0.5, 10}, reporting.HistogramConfig{BinWidth: 1, LogScale: true})
+	require.Len(t, belowOne.Bins, 2)
+	require.Equal(t, 2, belowOne.Bins[0].Count)
+
+	require.InDelta(t, 2.5, reporting.Percentile([]float64{1, 2, 3, 4}, 50), 1e-9)
+}
+
+func TestOrderValueDistribution(t *testing.T) {
+	dataset := importTestDataset(t,
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,iPhone 13,,400.00,40.00,0,paid,DE,,,Phones",
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,Case,,100.00,20.00,0,paid,DE,,,Accessories",
+		"ORD-2,2025-01-11T10:00:00Z,b@example.com,iPhone 12,,300.00,40.00,0,paid,AT,,,Phones",
+		"ORD-3,2025-01-12T10:00:00Z,c@example.com,Charger,,20.00,2.00,0,paid,AT,,,Accessories",
+	)
+
+	dist := dataset.OrderValueDistribution(reporting.RevenueBasisGross, reporting.ItemFilter{}, reporting.DefaultHistogramConfig())
+	require.Equal(t, 3, dist.Orders)
+	require.InDelta(t, 300, dist.Median, 1e-9)
+
+	phones := dataset.OrderValues(reporting.RevenueBasisGross, reporting.ItemFilter{Category: "Phones"})
+	require.Equal(t, []float64{300, 400}, phones)
+}
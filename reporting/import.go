@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"slices"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -28,6 +27,7 @@ type rawOrderItemRow struct {
 	ShippedAt     string
 	DeliveredAt   string
 	Category      string
+	Currency      string
 }
 
 type csvFieldIndex int
@@ -48,6 +48,7 @@ const (
 	csvFieldShippedAt
 	csvFieldDeliveredAt
 	csvFieldCategory
+	csvFieldCurrency
 )
 
 func (f csvField) String() string {
@@ -78,6 +79,8 @@ func (f csvField) String() string {
 		return "delivered_at"
 	case csvFieldCategory:
 		return "category"
+	case csvFieldCurrency:
+		return "currency"
 	default:
 		return "UNKNOWN FIELD"
 	}
@@ -101,8 +104,16 @@ func requiredFields() []csvField {
 	}
 }
 
+func optionalFields() []csvField {
+	return []csvField{
+		csvFieldCurrency,
+	}
+}
+
+const csvFieldMissing csvFieldIndex = -1
+
 func lookupFieldIndices(headerFields []string) ([]csvFieldIndex, error) {
-	indices := make([]csvFieldIndex, len(requiredFields()))
+	indices := make([]csvFieldIndex, len(requiredFields())+len(optionalFields()))
 	for _, reqfield := range requiredFields() {
 		idx := slices.Index(headerFields, reqfield.String())
 		if idx == -1 {
@@ -110,10 +121,65 @@ func lookupFieldIndices(headerFields []string) ([]csvFieldIndex, error) {
 		}
 		indices[reqfield] = csvFieldIndex(idx)
 	}
+	for _, optfield := range optionalFields() {
+		indices[optfield] = csvFieldIndex(slices.Index(headerFields, optfield.String()))
+	}
 	return indices, nil
 }
 
-func ImportOrderDatasetFromCSV(r io.Reader) (*OrderDataset, error) {
+func optionalField(fields []string, idx csvFieldIndex) string {
+	if idx == csvFieldMissing {
+		return ""
+	}
+	return fields[idx]
+}
+
+type ImportOption func(*importConfig)
+
+type importConfig struct {
+	validationRules   []ValidationRule
+	reportingCurrency Currency
+	fxRates           *FXRates
+	vatRates          *VATRates
+	calendar          *HolidayCalendar
+}
+
+func WithValidationRules(rules ...ValidationRule) ImportOption {
+	return func(cfg *importConfig) {
+		cfg.validationRules = rules
+	}
+}
+
+func WithReportingCurrency(currency Currency, fx *FXRates) ImportOption {
+	return func(cfg *importConfig) {
+		cfg.reportingCurrency = currency
+		cfg.fxRates = fx
+	}
+}
+
+func WithVATRates(vat *VATRates) ImportOption {
+	return func(cfg *importConfig) {
+		cfg.vatRates = vat
+	}
+}
+
+// WithHolidayCalendar counts delivery times in business days of the item's
+// country, skipping the calendar's holidays.
+func WithHolidayCalendar(cal *HolidayCalendar) ImportOption {
+	return func(cfg *importConfig) {
+		cfg.calendar = cal
+	}
+}
+
+func ImportOrderDatasetFromCSV(r io.Reader, opts ...ImportOption) (*OrderDataset, error) {
+	cfg := importConfig{
+		validationRules:   DefaultValidationRules(),
+		reportingCurrency: DefaultCurrency,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	csvr := csv.NewReader(r)
 
 	headerFields, err := csvr.Read()
@@ -125,10 +191,15 @@ func ImportOrderDatasetFromCSV(r io.Reader) (*OrderDataset, error) {
 		return nil, err
 	}
 
-	ds := &OrderDataset{
-		allItems: make([]OrderItem, 0, 300_000),
-		orders:   map[OrderID][]OrderItem{},
+	ds := newOrderDataset(300_000)
+	ds.reportingCurrency = cfg.reportingCurrency
+	ds.hasVATRates = cfg.vatRates != nil
+	ds.calendar = cfg.calendar
+	rules := cfg.validationRules
+	if cfg.vatRates != nil {
+		rules = append(slices.Clip(rules), missingVATRateRule(cfg.vatRates))
 	}
+	quality := newDataQualityReport(rules)
 	for {
 		fields, err := csvr.Read()
 		if err != nil {
@@ -151,21 +222,23 @@ func ImportOrderDatasetFromCSV(r io.Reader) (*OrderDataset, error) {
 			ShippedAt:     fields[fieldIndices[csvFieldShippedAt]],
 			DeliveredAt:   fields[fieldIndices[csvFieldDeliveredAt]],
 			Category:      fields[fieldIndices[csvFieldCategory]],
+			Currency:      optionalField(fields, fieldIndices[csvFieldCurrency]),
 		}
 		orderItem, err := parseOrderItem(raw)
 		if err != nil {
 			return nil, fmt.Errorf("parse order: %w", err)
 		}
-		ds.add(orderItem)
+		row, _ := csvr.FieldPos(0)
+		quality.check(row, orderItem)
+		orderItem, err = convertOrderItem(orderItem, cfg.reportingCurrency, cfg.fxRates)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", row, err)
+		}
+		ds.add(applyVATRate(orderItem, cfg.vatRates))
 	}
 
-	deliveryDurations := make([]time.Duration, len(ds.deliveryDurations))
-	copy(deliveryDurations, ds.deliveryDurations)
-
-	sort.Slice(deliveryDurations, func(i, j int) bool {
-		return deliveryDurations[i] < deliveryDurations[j]
-	})
-	ds.sortedDeliveryDurations = deliveryDurations
+	ds.finalize()
+	ds.dataQuality = quality
 	return ds, nil
 }
 
@@ -211,21 +284,33 @@ func parseOrderItem(raw rawOrderItemRow) (OrderItem, error) {
 			return errorf("parse delivered_at: %w", err)
 		}
 	}
+	currency := DefaultCurrency
+	if raw.Currency != "" {
+		currency = Currency(strings.ToUpper(raw.Currency))
+	}
 	return OrderItem{
-		OrderID:        OrderID(raw.OrderID),
-		NumericOrderID: int32(numericOrderID),
-		OrderedAt:      parsedOrderedAt,
-		CustomerEmail:  raw.CustomerEmail,
-		ItemName:       raw.ItemName,
-		ItemSpecs:      parseItemSpecs(raw.ItemSpecs),
-		ItemPrice:      parsedItemPrice,
-		Commission:     parsedCommission,
-		Refunded:       parsedRefunded,
-		PaymentStatus:  raw.PaymentStatus,
-		Country:        raw.Country,
-		ShippedAt:      parsedShippedAt,
-		DeliveredAt:    parsedDeliveredAt,
-		Category:       parseCategoryPath(raw.Category),
+		OrderID:          OrderID(raw.OrderID),
+		NumericOrderID:   int32(numericOrderID),
+		OrderedAt:        parsedOrderedAt,
+		CustomerEmail:    raw.CustomerEmail,
+		ItemName:         raw.ItemName,
+		ItemSpecs:        parseItemSpecs(raw.ItemSpecs),
+		ItemPrice:        parsedItemPrice,
+		Commission:       parsedCommission,
+		Refunded:         parsedRefunded,
+		PaymentStatus:    ParsePaymentStatus(raw.PaymentStatus),
+		RawPaymentStatus: raw.PaymentStatus,
+		Country:          raw.Country,
+		ShippedAt:        parsedShippedAt,
+		DeliveredAt:      parsedDeliveredAt,
+		Category:         parseCategoryPath(raw.Category),
+		Currency:         currency,
+		Original: OriginalAmounts{
+			Currency:   currency,
+			ItemPrice:  parsedItemPrice,
+			Commission: parsedCommission,
+			Refunded:   parsedRefunded,
+		},
 	}, nil
 }
 
@@ -251,10 +336,7 @@ func parseItemSpecs(raw string) []ItemSpec {
 		if !found {
 			continue
 		}
-		specs = append(specs, ItemSpec{
-			Key:      key,
-			RawValue: rawValue,
-		})
+		specs = append(specs, parseItemSpec(key, rawValue))
 	}
 	return specs
 }
@@ -2,6 +2,7 @@ package reporting_test
 
 import (
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -9,6 +10,16 @@ import (
 	"refurbed.com/hackathon/reporting"
 )
 
+const testCSVHeader = "order_id,ordered_at,customer_email,item_name,item_specs,item_price,commission,refunded,payment_status,country,shipped_at,delivered_at,category"
+
+func importTestDataset(t *testing.T, rows ...string) *reporting.OrderDataset {
+	t.Helper()
+	in := strings.NewReader(testCSVHeader + "\n" + strings.Join(rows, "\n") + "\n")
+	dataset, err := reporting.ImportOrderDatasetFromCSV(in)
+	require.NoError(t, err)
+	return dataset
+}
+
 func TestImportOrderDatasetFromCSV(t *testing.T) {
 	in, err := os.Open("orders_v3.csv")
 	require.NoError(t, err)
@@ -0,0 +1,43 @@
+package reporting
+
+import (
+	"fmt"
+	"time"
+)
+
+// intervalBuckets holds one bucket per interval from start to end, both
+// truncated to the interval, in time order.
+type intervalBuckets[T any] struct {
+	start    time.Time
+	interval time.Duration
+	buckets  []T
+}
+
+func newIntervalBuckets[T any](start, end time.Time, interval time.Duration, newBucket func(from, to time.Time) T) *intervalBuckets[T] {
+	b := &intervalBuckets[T]{start: start.Truncate(interval), interval: interval}
+	for date := b.start; !date.After(end); date = date.Add(interval) {
+		b.buckets = append(b.buckets, newBucket(date, date.Add(interval)))
+	}
+	return b
+}
+
+// at returns the bucket t falls into, nil when t lies outside the buckets.
+func (b *intervalBuckets[T]) at(t time.Time) *T {
+	date := t.Truncate(b.interval)
+	if date.Before(b.start) {
+		return nil
+	}
+	idx := int(date.Sub(b.start) / b.interval)
+	if idx >= len(b.buckets) {
+		return nil
+	}
+	return &b.buckets[idx]
+}
+
+func dayTitle(from, to time.Time) string {
+	return fmt.Sprintf("Day %s", from.Format("2006-01-02"))
+}
+
+func weekTitle(from, to time.Time) string {
+	return fmt.Sprintf("Week %s - %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+}
@@ -0,0 +1,253 @@
+package reporting
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/shopspring/decimal"
+)
+
+// itemKey names an item, optionally together with its specs, for example
+// "iPhone 13 (storage=128GB, colour=black)".
+func itemKey(item OrderItem, withSpecs bool) string {
+	if !withSpecs || len(item.ItemSpecs) == 0 {
+		return item.ItemName
+	}
+	specs := make([]string, len(item.ItemSpecs))
+	for i, spec := range item.ItemSpecs {
+		specs[i] = spec.Key + "=" + spec.Value
+	}
+	return item.ItemName + " (" + strings.Join(specs, ", ") + ")"
+}
+
+func (ds *OrderDataset) addItemIndex(itemID orderItemID, item OrderItem) {
+	if ds.features.item == nil {
+		ds.features.item = map[string]*roaring.Bitmap{}
+	}
+	if ds.features.itemVariant == nil {
+		ds.features.itemVariant = map[string]*roaring.Bitmap{}
+	}
+	addToIndex(ds.features.item, itemKey(item, false), itemID)
+	addToIndex(ds.features.itemVariant, itemKey(item, true), itemID)
+}
+
+func addToIndex(index map[string]*roaring.Bitmap, key string, itemID orderItemID) {
+	bitmap := index[key]
+	if bitmap == nil {
+		bitmap = roaring.New()
+		index[key] = bitmap
+	}
+	bitmap.Add(uint32(itemID))
+}
+
+func (ds *OrderDataset) itemIndex(withSpecs bool) map[string]*roaring.Bitmap {
+	if withSpecs {
+		return ds.features.itemVariant
+	}
+	return ds.features.item
+}
+
+// AllItemNames returns the distinct item names, with their specs when
+// withSpecs is set, sorted by name.
+func (ds *OrderDataset) AllItemNames(withSpecs bool) []string {
+	return slices.Sorted(maps.Keys(ds.itemIndex(withSpecs)))
+}
+
+// ItemsNamed returns the recognized order items with the given name as
+// returned by AllItemNames.
+func (ds *OrderDataset) ItemsNamed(name string, withSpecs bool) []OrderItem {
+	bitmap := ds.itemIndex(withSpecs)[name]
+	if bitmap == nil {
+		return nil
+	}
+	items := roaring.And(bitmap, ds.features.recognizedItem)
+	res := make([]OrderItem, 0, items.GetCardinality())
+	items.Iterate(func(itemID uint32) bool {
+		res = append(res, ds.allItems[itemID])
+		return true
+	})
+	return res
+}
+
+type ItemRanking int
+
+const (
+	RankByRevenue ItemRanking = iota
+	RankByUnits
+	RankByReturnRate
+	RankByCommission
+)
+
+func (r ItemRanking) String() string {
+	switch r {
+	case RankByRevenue:
+		return "revenue"
+	case RankByUnits:
+		return "units"
+	case RankByReturnRate:
+		return "return rate"
+	case RankByCommission:
+		return "commission"
+	default:
+		return "UNKNOWN RANKING"
+	}
+}
+
+func AllItemRankings() []ItemRanking {
+	return []ItemRanking{RankByRevenue, RankByUnits, RankByReturnRate, RankByCommission}
+}
+
+type ItemStats struct {
+	Name       string
+	Units      int
+	Returned   int
+	Revenue    decimal.Decimal
+	Commission decimal.Decimal
+}
+
+func (s ItemStats) ReturnRate() float64 {
+	return s.ReturnRateEstimate().Rate()
+}
+
+func (s ItemStats) ReturnRateEstimate() RateEstimate {
+	return NewRateEstimate(s.Returned, s.Units)
+}
+
+// ItemStats sums up the recognized order items per item name.
+func (ds *OrderDataset) ItemStats(basis RevenueBasis, withSpecs bool) []ItemStats {
+	index := ds.itemIndex(withSpecs)
+	res := make([]ItemStats, 0, len(index))
+	for name, bitmap := range index {
+		stats := ItemStats{Name: name}
+		bitmap.Iterate(func(itemID uint32) bool {
+			item := ds.allItems[itemID]
+			if !ds.recognizes(item) {
+				return true
+			}
+			stats.Units++
+			if ds.features.returned.Contains(itemID) {
+				stats.Returned++
+			}
+			stats.Revenue = stats.Revenue.Add(item.revenue(basis))
+			stats.Commission = stats.Commission.Add(item.Commission)
+			return true
+		})
+		if stats.Units > 0 {
+			res = append(res, stats)
+		}
+	}
+	slices.SortFunc(res, func(a, b ItemStats) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return res
+}
+
+// TopItems returns the n items ranked highest, ties broken by name. A
+// non-positive n returns every item.
+func (ds *OrderDataset) TopItems(by ItemRanking, basis RevenueBasis, withSpecs bool, n int) []ItemStats {
+	res := ds.ItemStats(basis, withSpecs)
+	slices.SortStableFunc(res, func(a, b ItemStats) int {
+		switch by {
+		case RankByUnits:
+			return cmp.Compare(b.Units, a.Units)
+		case RankByReturnRate:
+			return cmp.Compare(b.ReturnRate(), a.ReturnRate())
+		case RankByCommission:
+			return b.Commission.Cmp(a.Commission)
+		default:
+			return b.Revenue.Cmp(a.Revenue)
+		}
+	})
+	if n > 0 && n < len(res) {
+		res = res[:n]
+	}
+	return res
+}
+
+type ABCClass string
+
+const (
+	ClassA ABCClass = "A"
+	ClassB ABCClass = "B"
+	ClassC ABCClass = "C"
+)
+
+type ParetoItem struct {
+	ItemStats
+	RevenueShare    float64
+	CumulativeShare float64
+	Class           ABCClass
+}
+
+type ParetoReport struct {
+	// Items are sorted by revenue, highest first.
+	Items []ParetoItem
+	// ItemShareFor80 is the share of items that together drive 80% of the
+	// revenue.
+	ItemShareFor80 float64
+}
+
+// ParetoAnalysis classifies items into A, the items driving the first 80% of
+// revenue, B, the next 15%, and C, the rest.
+func (ds *OrderDataset) ParetoAnalysis(basis RevenueBasis, withSpecs bool) ParetoReport {
+	ranked := ds.TopItems(RankByRevenue, basis, withSpecs, 0)
+
+	total := decimal.Zero
+	for _, s := range ranked {
+		total = total.Add(s.Revenue)
+	}
+
+	var report ParetoReport
+	cumulative := decimal.Zero
+	for i, s := range ranked {
+		item := ParetoItem{ItemStats: s, Class: ClassC}
+		previousShare := 0.0
+		if total.IsPositive() {
+			previousShare = cumulative.Div(total).InexactFloat64()
+			cumulative = cumulative.Add(s.Revenue)
+			item.RevenueShare = s.Revenue.Div(total).InexactFloat64()
+			item.CumulativeShare = cumulative.Div(total).InexactFloat64()
+		}
+		switch {
+		case previousShare < 0.8:
+			item.Class = ClassA
+			report.ItemShareFor80 = float64(i+1) / float64(len(ranked))
+		case previousShare < 0.95:
+			item.Class = ClassB
+		}
+		report.Items = append(report.Items, item)
+	}
+	return report
+}
+
+// ABCCounts returns the number of items per class.
+func (r ParetoReport) ABCCounts() map[ABCClass]int {
+	counts := map[ABCClass]int{}
+	for _, item := range r.Items {
+		counts[item.Class]++
+	}
+	return counts
+}
+
+type ParetoPoint struct {
+	ItemShare    float64
+	RevenueShare float64
+}
+
+// Curve samples the cumulative revenue share at points evenly spaced shares
+// of items.
+func (r ParetoReport) Curve(points int) []ParetoPoint {
+	if len(r.Items) == 0 || points <= 0 {
+		return nil
+	}
+	res := make([]ParetoPoint, points)
+	for i := range res {
+		itemShare := float64(i+1) / float64(points)
+		n := max(int(itemShare*float64(len(r.Items))+0.5), 1)
+		res[i] = ParetoPoint{ItemShare: itemShare, RevenueShare: r.Items[n-1].CumulativeShare}
+	}
+	return res
+}
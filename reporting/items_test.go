@@ -0,0 +1,41 @@
+package reporting_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"refurbed.com/hackathon/reporting"
+)
+
+func TestTopItemsAndPareto(t *testing.T) {
+	dataset := importTestDataset(t,
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,iPhone 13,storage=128GB,800.00,40.00,0,paid,DE,,,Phones",
+		"ORD-2,2025-01-11T10:00:00Z,b@example.com,iPhone 13,storage=256GB,900.00,40.00,0,paid,DE,,,Phones",
+		"ORD-3,2025-01-12T10:00:00Z,c@example.com,Case,,20.00,2.00,20.00,paid,AT,,,Accessories",
+		"ORD-3,2025-01-12T10:00:00Z,c@example.com,Case,,20.00,2.00,0,paid,AT,,,Accessories",
+		"ORD-3,2025-01-12T10:00:00Z,c@example.com,Case,,20.00,2.00,0,paid,AT,,,Accessories",
+		"ORD-4,2025-01-13T10:00:00Z,d@example.com,Charger,,260.00,10.00,0,paid,AT,,,Accessories",
+	)
+
+	top := dataset.TopItems(reporting.RankByRevenue, reporting.RevenueBasisGross, false, 2)
+	require.Len(t, top, 2)
+	require.Equal(t, "iPhone 13", top[0].Name)
+	require.Equal(t, 2, top[0].Units)
+	require.Equal(t, "Charger", top[1].Name)
+
+	byUnits := dataset.TopItems(reporting.RankByUnits, reporting.RevenueBasisGross, false, 1)
+	require.Equal(t, "Case", byUnits[0].Name)
+	require.InDelta(t, 1.0/3, byUnits[0].ReturnRate(), 1e-9)
+
+	require.Equal(t, []string{"Case", "Charger", "iPhone 13 (storage=128GB)", "iPhone 13 (storage=256GB)"}, dataset.AllItemNames(true))
+	require.Len(t, dataset.ItemsNamed("iPhone 13 (storage=256GB)", true), 1)
+
+	report := dataset.ParetoAnalysis(reporting.RevenueBasisGross, false)
+	require.Len(t, report.Items, 3)
+	require.Equal(t, reporting.ClassA, report.Items[0].Class)
+	require.Equal(t, reporting.ClassB, report.Items[1].Class)
+	require.Equal(t, reporting.ClassC, report.Items[2].Class)
+	require.InDelta(t, 1.0/3, report.ItemShareFor80, 1e-9)
+	require.InDelta(t, 1.0, report.Curve(3)[2].RevenueShare, 1e-9)
+}
@@ -0,0 +1,715 @@
+package reporting
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type MetricLevel string
+
+const (
+	// MetricLevelItem aggregates the expression over order items.
+	MetricLevelItem MetricLevel = "item"
+	// MetricLevelOrder rolls the expression up per order first and then
+	// aggregates over orders.
+	MetricLevelOrder MetricLevel = "order"
+)
+
+type MetricAggregation string
+
+const (
+	AggregationSum   MetricAggregation = "sum"
+	AggregationAvg   MetricAggregation = "avg"
+	AggregationCount MetricAggregation = "count"
+	AggregationMin   MetricAggregation = "min"
+	AggregationMax   MetricAggregation = "max"
+)
+
+type MetricUnit string
+
+const (
+	MetricUnitNumber  MetricUnit = "number"
+	MetricUnitMoney   MetricUnit = "money"
+	MetricUnitPercent MetricUnit = "percent"
+)
+
+// MetricFilter keeps items whose field is one of Values, or none of them when
+// Op is "not_in". Fields are country, category, payment_status, currency and
+// item_name.
+type MetricFilter struct {
+	Field  string   `json:"field"`
+	Op     string   `json:"op"`
+	Values []string `json:"values"`
+}
+
+// MetricDefinition declares a metric as an arithmetic expression over order
+// item fields, for example "item_price - refunded" or "refunded > 0".
+// Comparisons evaluate to 1 or 0 and a division by zero evaluates to 0. With a
+// Denominator the metric is a ratio of the aggregated expressions, such as
+// sum(commission) / sum(item_price). Metrics only see the items with a
+// recognized payment status unless IncludeUnrecognized is set, which rates such
+// as the return rate use to count every item.
+type MetricDefinition struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Expression  string            `json:"expression"`
+	Denominator string            `json:"denominator"`
+	Filters     []MetricFilter    `json:"filters"`
+	Level       MetricLevel       `json:"level"`
+	Rollup      MetricAggregation `json:"rollup"`
+	Aggregation MetricAggregation `json:"aggregation"`
+	Unit        MetricUnit        `json:"unit"`
+
+	IncludeUnrecognized bool `json:"include_unrecognized"`
+}
+
+type Metric struct {
+	MetricDefinition
+	expr    metricExpr
+	denom   metricExpr
+	filters []func(item OrderItem) bool
+}
+
+func (m *Metric) matches(item OrderItem) bool {
+	for _, f := range m.filters {
+		if !f(item) {
+			return false
+		}
+	}
+	return true
+}
+
+type MetricRegistry struct {
+	metrics []*Metric
+	byName  map[string]*Metric
+}
+
+func NewMetricRegistry(defs []MetricDefinition) (*MetricRegistry, error) {
+	reg := &MetricRegistry{byName: map[string]*Metric{}}
+	for _, def := range defs {
+		if err := reg.Register(def); err != nil {
+			return nil, err
+		}
+	}
+	return reg, nil
+}
+
+// LoadMetricRegistryFromJSON reads a JSON array of metric definitions.
+func LoadMetricRegistryFromJSON(r io.Reader) (*MetricRegistry, error) {
+	var defs []MetricDefinition
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&defs); err != nil {
+		return nil, fmt.Errorf("decode metric definitions: %w", err)
+	}
+	return NewMetricRegistry(defs)
+}
+
+func DefaultMetricRegistry() *MetricRegistry {
+	reg, err := NewMetricRegistry(DefaultMetricDefinitions())
+	if err != nil {
+		panic(err)
+	}
+	return reg
+}
+
+func DefaultMetricDefinitions() []MetricDefinition {
+	paid := []MetricFilter{{Field: "payment_status", Op: "in", Values: []string{"paid", "refunded"}}}
+	return []MetricDefinition{
+		{Name: "gmv", Description: "Sum of item prices", Expression: "item_price", Aggregation: AggregationSum, Unit: MetricUnitMoney},
+		{Name: "revenue", Description: "Item prices minus refunds", Expression: "item_price - refunded", Aggregation: AggregationSum, Unit: MetricUnitMoney},
+		{Name: "paid_revenue", Description: "Revenue of paid and refunded items, whatever the recognized payment statuses", Expression: "item_price - refunded", Filters: paid, Aggregation: AggregationSum, Unit: MetricUnitMoney, IncludeUnrecognized: true},
+		{Name: "net_revenue", Description: "Revenue excluding VAT", Expression: "net_revenue", Aggregation: AggregationSum, Unit: MetricUnitMoney},
+		{Name: "commission", Description: "Commission on item prices", Expression: "commission", Aggregation: AggregationSum, Unit: MetricUnitMoney},
+		{Name: "net_commission", Description: "Commission net of refunds", Expression: "net_commission", Aggregation: AggregationSum, Unit: MetricUnitMoney},
+		{Name: "orders", Description: "Number of orders", Expression: "1", Level: MetricLevelOrder, Rollup: AggregationMax, Aggregation: AggregationSum, Unit: MetricUnitNumber},
+		{Name: "aov", Description: "Average gross order value", Expression: "item_price", Level: MetricLevelOrder, Rollup: AggregationSum, Aggregation: AggregationAvg, Unit: MetricUnitMoney},
+		{Name: "net_aov", Description: "Average order value excluding VAT", Expression: "net_price", Level: MetricLevelOrder, Rollup: AggregationSum, Aggregation: AggregationAvg, Unit: MetricUnitMoney},
+		{Name: "item_return_rate", Description: "Share of items with a refund", Expression: "refunded > 0", Aggregation: AggregationAvg, Unit: MetricUnitPercent, IncludeUnrecognized: true},
+		{Name: "order_return_rate", Description: "Share of orders with a refunded item", Expression: "refunded > 0", Level: MetricLevelOrder, Rollup: AggregationMax, Aggregation: AggregationAvg, Unit: MetricUnitPercent, IncludeUnrecognized: true},
+		{Name: "take_rate", Description: "Commission divided by the gross merchandise value", Expression: "commission", Denominator: "item_price", Aggregation: AggregationSum, Unit: MetricUnitPercent},
+		{Name: "net_take_rate", Description: "Commission net of refunds divided by the gross merchandise value", Expression: "net_commission", Denominator: "item_price", Aggregation: AggregationSum, Unit: MetricUnitPercent},
+	}
+}
+
+func (reg *MetricRegistry) Register(def MetricDefinition) error {
+	if def.Name == "" {
+		return fmt.Errorf("metric without name")
+	}
+	if _, ok := reg.byName[def.Name]; ok {
+		return fmt.Errorf("metric %q is defined twice", def.Name)
+	}
+	def.Level = cmp.Or(def.Level, MetricLevelItem)
+	def.Rollup = cmp.Or(def.Rollup, AggregationSum)
+	def.Aggregation = cmp.Or(def.Aggregation, AggregationSum)
+	def.Unit = cmp.Or(def.Unit, MetricUnitNumber)
+	if def.Level != MetricLevelItem && def.Level != MetricLevelOrder {
+		return fmt.Errorf("metric %q: unknown level %q", def.Name, def.Level)
+	}
+	for _, a := range []MetricAggregation{def.Rollup, def.Aggregation} {
+		if !slices.Contains([]MetricAggregation{AggregationSum, AggregationAvg, AggregationCount, AggregationMin, AggregationMax}, a) {
+			return fmt.Errorf("metric %q: unknown aggregation %q", def.Name, a)
+		}
+	}
+
+	expr, fields, err := parseMetricExpr(def.Expression)
+	if err != nil {
+		return fmt.Errorf("metric %q: %w", def.Name, err)
+	}
+	m := &Metric{MetricDefinition: def, expr: expr}
+	if def.Denominator != "" {
+		var denomFields []string
+		m.denom, denomFields, err = parseMetricExpr(def.Denominator)
+		if err != nil {
+			return fmt.Errorf("metric %q: denominator: %w", def.Name, err)
+		}
+		fields = append(fields, denomFields...)
+	}
+	if slices.ContainsFunc(fields, func(f string) bool { return slices.Contains(deliveryMetricFields, f) }) {
+		m.filters = append(m.filters, delivered)
+	}
+	for _, f := range def.Filters {
+		filter, err := compileMetricFilter(f)
+		if err != nil {
+			return fmt.Errorf("metric %q: %w", def.Name, err)
+		}
+		m.filters = append(m.filters, filter)
+	}
+
+	reg.metrics = append(reg.metrics, m)
+	reg.byName[def.Name] = m
+	return nil
+}
+
+func (reg *MetricRegistry) All() []*Metric {
+	return slices.Clone(reg.metrics)
+}
+
+func (reg *MetricRegistry) Lookup(name string) (*Metric, bool) {
+	m, ok := reg.byName[name]
+	return m, ok
+}
+
+func compileMetricFilter(f MetricFilter) (func(item OrderItem) bool, error) {
+	var field func(item OrderItem) []string
+	switch f.Field {
+	case "country":
+		field = func(item OrderItem) []string { return []string{item.Country} }
+	case "category":
+		field = func(item OrderItem) []string {
+			res := make([]string, len(item.Category))
+			for i, c := range item.Category {
+				res[i] = string(c)
+			}
+			return res
+		}
+	case "payment_status":
+		field = func(item OrderItem) []string { return []string{item.PaymentStatus.String()} }
+	case "currency":
+		field = func(item OrderItem) []string { return []string{string(item.Currency)} }
+	case "item_name":
+		field = func(item OrderItem) []string { return []string{item.ItemName} }
+	default:
+		return nil, fmt.Errorf("unknown filter field %q", f.Field)
+	}
+
+	values := map[string]struct{}{}
+	for _, v := range f.Values {
+		if f.Field == "payment_status" {
+			v = ParsePaymentStatus(v).String()
+		}
+		values[v] = struct{}{}
+	}
+	anyMatch := func(item OrderItem) bool {
+		for _, v := range field(item) {
+			if _, ok := values[v]; ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch f.Op {
+	case "in", "":
+		return anyMatch, nil
+	case "not_in":
+		return func(item OrderItem) bool { return !anyMatch(item) }, nil
+	default:
+		return nil, fmt.Errorf("unknown filter op %q", f.Op)
+	}
+}
+
+type metricAccumulator struct {
+	sum   decimal.Decimal
+	count int64
+	min   decimal.Decimal
+	max   decimal.Decimal
+}
+
+func (a *metricAccumulator) add(v decimal.Decimal) {
+	if a.count == 0 || v.LessThan(a.min) {
+		a.min = v
+	}
+	if a.count == 0 || v.GreaterThan(a.max) {
+		a.max = v
+	}
+	a.sum = a.sum.Add(v)
+	a.count++
+}
+
+func (a *metricAccumulator) value(agg MetricAggregation) decimal.Decimal {
+	switch agg {
+	case AggregationAvg:
+		if a.count == 0 {
+			return decimal.Zero
+		}
+		return a.sum.Div(decimal.NewFromInt(a.count))
+	case AggregationCount:
+		return decimal.NewFromInt(a.count)
+	case AggregationMin:
+		return a.min
+	case AggregationMax:
+		return a.max
+	default:
+		return a.sum
+	}
+}
+
+// metricRatio accumulates the expression and, for ratio metrics, the
+// denominator side by side.
+type metricRatio struct {
+	num metricAccumulator
+	den metricAccumulator
+}
+
+func (r *metricRatio) add(m *Metric, num, den decimal.Decimal) {
+	r.num.add(num)
+	if m.denom != nil {
+		r.den.add(den)
+	}
+}
+
+func (r *metricRatio) value(m *Metric, agg MetricAggregation) decimal.Decimal {
+	if m.denom == nil {
+		return r.num.value(agg)
+	}
+	den := r.den.value(agg)
+	if den.IsZero() {
+		return decimal.Zero
+	}
+	return r.num.value(agg).Div(den)
+}
+
+type MetricGrouping int
+
+const (
+	GroupByNone MetricGrouping = iota
+	GroupByDay
+	GroupByWeek
+	GroupByCountry
+	GroupByCategory
+	GroupByItem
+	GroupByItemVariant
+)
+
+func (g MetricGrouping) String() string {
+	switch g {
+	case GroupByNone:
+		return "total"
+	case GroupByDay:
+		return "day"
+	case GroupByWeek:
+		return "week"
+	case GroupByCountry:
+		return "country"
+	case GroupByCategory:
+		return "category"
+	case GroupByItem:
+		return "item"
+	case GroupByItemVariant:
+		return "item variant"
+	default:
+		return "UNKNOWN GROUPING"
+	}
+}
+
+// MetricQuery evaluates a metric over the items matching Filter. Zero Start and
+// End do not restrict the order date.
+type MetricQuery struct {
+	Metric  *Metric
+	GroupBy MetricGrouping
+	Filter  ItemFilter
+	Start   time.Time
+	End     time.Time
+}
+
+type MetricRow struct {
+	Group string
+	Value decimal.Decimal
+	// Count is the number of items or orders, depending on the metric level,
+	// the value was aggregated from.
+	Count int64
+}
+
+func (q MetricQuery) groups(item OrderItem) []string {
+	switch q.GroupBy {
+	case GroupByDay:
+		return []string{item.OrderedAt.Truncate(24 * time.Hour).Format(time.DateOnly)}
+	case GroupByWeek:
+		return []string{item.OrderedAt.Truncate(7 * 24 * time.Hour).Format(time.DateOnly)}
+	case GroupByCountry:
+		return []string{item.Country}
+	case GroupByCategory:
+		res := make([]string, len(item.Category))
+		for i, c := range item.Category {
+			res[i] = string(c)
+		}
+		return res
+	case GroupByItem:
+		return []string{itemKey(item, false)}
+	case GroupByItemVariant:
+		return []string{itemKey(item, true)}
+	default:
+		return []string{"Total"}
+	}
+}
+
+func (q MetricQuery) includes(ds *OrderDataset, item OrderItem) bool {
+	if !q.Metric.IncludeUnrecognized && !ds.recognizes(item) {
+		return false
+	}
+	if !q.Start.IsZero() && item.OrderedAt.Before(q.Start) {
+		return false
+	}
+	if !q.End.IsZero() && !item.OrderedAt.Before(q.End) {
+		return false
+	}
+	return q.Filter.Matches(item) && q.Metric.matches(item)
+}
+
+// Query evaluates the metric per group, sorted by group. An item in several
+// categories counts toward each of them.
+func (ds *OrderDataset) Query(q MetricQuery) []MetricRow {
+	m := q.Metric
+	results := map[string]*metricRatio{}
+	perOrder := map[string]map[OrderID]*metricRatio{}
+
+	for _, item := range ds.allItems {
+		if !q.includes(ds, item) {
+			continue
+		}
+		num, den := m.expr(item), decimal.Zero
+		if m.denom != nil {
+			den = m.denom(item)
+		}
+		for _, g := range q.groups(item) {
+			if m.Level == MetricLevelOrder {
+				if perOrder[g] == nil {
+					perOrder[g] = map[OrderID]*metricRatio{}
+				}
+				if perOrder[g][item.OrderID] == nil {
+					perOrder[g][item.OrderID] = &metricRatio{}
+				}
+				perOrder[g][item.OrderID].add(m, num, den)
+				continue
+			}
+			if results[g] == nil {
+				results[g] = &metricRatio{}
+			}
+			results[g].add(m, num, den)
+		}
+	}
+
+	for g, orders := range perOrder {
+		results[g] = &metricRatio{}
+		for _, order := range orders {
+			results[g].add(m, order.num.value(m.Rollup), order.den.value(m.Rollup))
+		}
+	}
+
+	res := make([]MetricRow, 0, len(results))
+	for _, g := range slices.Sorted(maps.Keys(results)) {
+		res = append(res, MetricRow{Group: g, Value: results[g].value(m, m.Aggregation), Count: results[g].num.count})
+	}
+	return res
+}
+
+// Evaluate returns the metric over the whole dataset.
+func (ds *OrderDataset) Evaluate(m *Metric) decimal.Decimal {
+	return ds.evaluate(m).Value
+}
+
+// EvaluateEstimate evaluates a share metric, the average of a percentage
+// such as a return rate, with its interval. ok is false for other metrics.
+func (ds *OrderDataset) EvaluateEstimate(m *Metric) (RateEstimate, bool) {
+	return m.RateEstimate(ds.evaluate(m))
+}
+
+// RateEstimate is the value of a row of a share metric with its interval. ok
+// is false for other metrics.
+func (m *Metric) RateEstimate(row MetricRow) (RateEstimate, bool) {
+	if m.Aggregation != AggregationAvg || m.Unit != MetricUnitPercent || m.denom != nil {
+		return RateEstimate{}, false
+	}
+	return NewShareEstimate(row.Value.InexactFloat64(), int(row.Count)), true
+}
+
+func (ds *OrderDataset) evaluate(m *Metric) MetricRow {
+	rows := ds.Query(MetricQuery{Metric: m})
+	if len(rows) == 0 {
+		return MetricRow{}
+	}
+	return rows[0]
+}
+
+func WriteMetricRowsCSV(w io.Writer, q MetricQuery, rows []MetricRow) error {
+	csvw := csv.NewWriter(w)
+	err := csvw.Write([]string{"metric", q.GroupBy.String(), "value", "count"})
+	if err != nil {
+		return fmt.Errorf("write CSV header: %w", err)
+	}
+	for _, r := range rows {
+		err := csvw.Write([]string{q.Metric.Name, r.Group, r.Value.StringFixed(4), fmt.Sprintf("%d", r.Count)})
+		if err != nil {
+			return fmt.Errorf("write CSV row: %w", err)
+		}
+	}
+	csvw.Flush()
+	return csvw.Error()
+}
+
+type metricExpr func(item OrderItem) decimal.Decimal
+
+var metricFields = map[string]metricExpr{
+	"item_price":     func(item OrderItem) decimal.Decimal { return item.ItemPrice },
+	"refunded":       func(item OrderItem) decimal.Decimal { return item.Refunded },
+	"commission":     func(item OrderItem) decimal.Decimal { return item.Commission },
+	"net_commission": OrderItem.NetCommission,
+	"revenue":        OrderItem.Revenue,
+	"net_price":      OrderItem.NetPrice,
+	"net_revenue":    OrderItem.NetRevenue,
+	"vat":            OrderItem.VATAmount,
+	"vat_rate":       func(item OrderItem) decimal.Decimal { return item.VATRate },
+	"delivery_hours": func(item OrderItem) decimal.Decimal {
+		return decimal.NewFromFloat(item.DeliveredIn().Hours())
+	},
+	"delivery_business_days": func(item OrderItem) decimal.Decimal {
+		return decimal.NewFromInt(int64(item.businessDaysToDelivery))
+	},
+}
+
+// deliveryMetricFields are only defined for delivered items. Metrics using
+// them skip the other items rather than counting them as 0.
+var deliveryMetricFields = []string{"delivery_hours", "delivery_business_days"}
+
+func delivered(item OrderItem) bool {
+	return !item.DeliveredAt.IsZero()
+}
+
+func boolDecimal(b bool) decimal.Decimal {
+	if b {
+		return decimal.NewFromInt(1)
+	}
+	return decimal.Zero
+}
+
+// metricParser is a recursive descent parser for
+//
+//	expr    = sum [ ("<" | "<=" | ">" | ">=" | "==" | "!=") sum ]
+//	sum     = product { ("+" | "-") product }
+//	product = unary { ("*" | "/") unary }
+//	unary   = "-" unary | number | field | "(" expr ")"
+type metricParser struct {
+	tokens []string
+	pos    int
+	// fields are the fields the expression refers to.
+	fields []string
+}
+
+func parseMetricExpr(s string) (metricExpr, []string, error) {
+	tokens, err := tokenizeMetricExpr(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil, fmt.Errorf("empty expression")
+	}
+	p := &metricParser{tokens: tokens}
+	expr, err := p.expr()
+	if err != nil {
+		return nil, nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, nil, fmt.Errorf("unexpected %q in expression %q", p.tokens[p.pos], s)
+	}
+	return expr, p.fields, nil
+}
+
+func tokenizeMetricExpr(s string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case strings.ContainsRune("+-*/()", rune(c)):
+			tokens = append(tokens, string(c))
+			i++
+		case strings.ContainsRune("<>=!", rune(c)):
+			if i+1 < len(s) && s[i+1] == '=' {
+				tokens = append(tokens, s[i:i+2])
+				i += 2
+			} else if c == '<' || c == '>' {
+				tokens = append(tokens, string(c))
+				i++
+			} else {
+				return nil, fmt.Errorf("unexpected %q in expression %q", c, s)
+			}
+		case c == '.' || c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z':
+			j := i
+			for j < len(s) && (s[j] == '.' || s[j] == '_' || s[j] >= '0' && s[j] <= '9' || s[j] >= 'a' && s[j] <= 'z') {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected %q in expression %q", c, s)
+		}
+	}
+	return tokens, nil
+}
+
+func (p *metricParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *metricParser) expr() (metricExpr, error) {
+	left, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	op := p.peek()
+	var cmp func(a, b decimal.Decimal) bool
+	switch op {
+	case "<":
+		cmp = decimal.Decimal.LessThan
+	case "<=":
+		cmp = decimal.Decimal.LessThanOrEqual
+	case ">":
+		cmp = decimal.Decimal.GreaterThan
+	case ">=":
+		cmp = decimal.Decimal.GreaterThanOrEqual
+	case "==":
+		cmp = decimal.Decimal.Equal
+	case "!=":
+		cmp = func(a, b decimal.Decimal) bool { return !a.Equal(b) }
+	default:
+		return left, nil
+	}
+	p.pos++
+	right, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	return func(item OrderItem) decimal.Decimal {
+		return boolDecimal(cmp(left(item), right(item)))
+	}, nil
+}
+
+func (p *metricParser) sum() (metricExpr, error) {
+	left, err := p.product()
+	if err != nil {
+		return nil, err
+	}
+	for op := p.peek(); op == "+" || op == "-"; op = p.peek() {
+		p.pos++
+		right, err := p.product()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		if op == "+" {
+			left = func(item OrderItem) decimal.Decimal { return l(item).Add(right(item)) }
+		} else {
+			left = func(item OrderItem) decimal.Decimal { return l(item).Sub(right(item)) }
+		}
+	}
+	return left, nil
+}
+
+func (p *metricParser) product() (metricExpr, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for op := p.peek(); op == "*" || op == "/"; op = p.peek() {
+		p.pos++
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		if op == "*" {
+			left = func(item OrderItem) decimal.Decimal { return l(item).Mul(right(item)) }
+		} else {
+			left = func(item OrderItem) decimal.Decimal {
+				divisor := right(item)
+				if divisor.IsZero() {
+					return decimal.Zero
+				}
+				return l(item).Div(divisor)
+			}
+		}
+	}
+	return left, nil
+}
+
+func (p *metricParser) unary() (metricExpr, error) {
+	tok := p.peek()
+	p.pos++
+	switch {
+	case tok == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case tok == "-":
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(item OrderItem) decimal.Decimal { return operand(item).Neg() }, nil
+	case tok == "(":
+		inner, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return inner, nil
+	case tok[0] == '.' || tok[0] >= '0' && tok[0] <= '9':
+		v, err := decimal.NewFromString(tok)
+		if err != nil {
+			return nil, fmt.Errorf("parse number %q: %w", tok, err)
+		}
+		return func(OrderItem) decimal.Decimal { return v }, nil
+	default:
+		field, ok := metricFields[tok]
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q", tok)
+		}
+		p.fields = append(p.fields, tok)
+		return field, nil
+	}
+}
@@ -0,0 +1,140 @@
+package reporting_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+
+	"refurbed.com/hackathon/reporting"
+)
+
+func TestMetricRegistry(t *testing.T) {
+	dataset := importTestDataset(t,
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,iPhone 13,,400.00,40.00,0,paid,DE,,,Phones>Smartphones",
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,Case,,100.00,20.00,100.00,refunded,DE,,,Accessories",
+		"ORD-2,2025-01-11T10:00:00Z,b@example.com,iPhone 12,,500.00,40.00,0,failed,AT,,,Phones>Smartphones",
+	)
+	paidOnly := dataset
+	dataset = dataset.WithRecognizedPaymentStatuses(reporting.AllPaymentStatuses()...)
+
+	registry := reporting.DefaultMetricRegistry()
+	evaluate := func(name string) decimal.Decimal {
+		m, ok := registry.Lookup(name)
+		require.True(t, ok, name)
+		return dataset.Evaluate(m)
+	}
+
+	require.True(t, decimal.NewFromInt(900).Equal(evaluate("revenue")))
+	require.True(t, decimal.NewFromInt(400).Equal(evaluate("paid_revenue")))
+	require.True(t, decimal.NewFromInt(2).Equal(evaluate("orders")))
+	require.True(t, decimal.NewFromInt(500).Equal(evaluate("aov")))
+	require.InDelta(t, 1.0/3, evaluate("item_return_rate").InexactFloat64(), 1e-9)
+	require.InDelta(t, 0.5, evaluate("order_return_rate").InexactFloat64(), 1e-9)
+
+	itemReturnRate, _ := registry.Lookup("item_return_rate")
+	estimate, ok := dataset.EvaluateEstimate(itemReturnRate)
+	require.True(t, ok)
+	require.Equal(t, 1, estimate.Successes)
+	require.Equal(t, 3, estimate.Trials)
+	require.InDelta(t, 1.0/3, estimate.Rate(), 1e-9)
+	require.InDelta(t, reporting.NewRateEstimate(1, 3).Lower, estimate.Lower, 1e-9)
+	aovMetric, _ := registry.Lookup("aov")
+	_, ok = dataset.EvaluateEstimate(aovMetric)
+	require.False(t, ok)
+
+	require.InDelta(t, dataset.TakeRate(), evaluate("take_rate").InexactFloat64(), 1e-9)
+	require.InDelta(t, 100.0/1000, evaluate("take_rate").InexactFloat64(), 1e-9)
+
+	revenue, _ := registry.Lookup("revenue")
+	require.True(t, decimal.NewFromInt(400).Equal(paidOnly.Evaluate(revenue)))
+
+	aov, _ := registry.Lookup("aov")
+	rows := dataset.Query(reporting.MetricQuery{Metric: aov, GroupBy: reporting.GroupByCategory})
+	require.Len(t, rows, 3)
+	require.Equal(t, "Accessories", rows[0].Group)
+	require.True(t, decimal.NewFromInt(100).Equal(rows[0].Value))
+	require.Equal(t, "Phones", rows[1].Group)
+	require.True(t, decimal.NewFromInt(450).Equal(rows[1].Value))
+	require.Equal(t, int64(2), rows[1].Count)
+}
+
+func TestLoadMetricRegistryFromJSON(t *testing.T) {
+	registry, err := reporting.LoadMetricRegistryFromJSON(strings.NewReader(`[
+		{"name": "de_net", "expression": "(item_price - refunded) * 2 / 4", "filters": [{"field": "country", "values": ["DE"]}], "unit": "money"}
+	]`))
+	require.NoError(t, err)
+
+	dataset := importTestDataset(t,
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,iPhone 13,,400.00,40.00,100.00,paid,DE,,,Phones>Smartphones",
+		"ORD-2,2025-01-11T10:00:00Z,b@example.com,iPhone 12,,500.00,40.00,0,paid,AT,,,Phones>Smartphones",
+	)
+	m, ok := registry.Lookup("de_net")
+	require.True(t, ok)
+	require.Equal(t, reporting.MetricLevelItem, m.Level)
+	require.True(t, decimal.NewFromInt(150).Equal(dataset.Evaluate(m)))
+
+	_, err = reporting.LoadMetricRegistryFromJSON(strings.NewReader(`[{"name": "broken", "expression": "item_price +"}]`))
+	require.Error(t, err)
+	_, err = reporting.LoadMetricRegistryFromJSON(strings.NewReader(`[{"name": "broken", "expression": "price"}]`))
+	require.Error(t, err)
+}
+
+func TestMetricsIncludeUnrecognizedItems(t *testing.T) {
+	dataset := importTestDataset(t,
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,iPhone 13,,400.00,40.00,0,paid,DE,,,Phones",
+		"ORD-2,2025-01-11T10:00:00Z,b@example.com,iPhone 12,,300.00,30.00,100.00,pending,DE,,,Phones",
+	)
+
+	registry := reporting.DefaultMetricRegistry()
+	itemReturnRate, _ := registry.Lookup("item_return_rate")
+	require.InDelta(t, 0.5, dataset.Evaluate(itemReturnRate).InexactFloat64(), 1e-9)
+	require.InDelta(t, dataset.ReturnRateEstimate().Rate(), dataset.Evaluate(itemReturnRate).InexactFloat64(), 1e-9)
+	orderReturnRate, _ := registry.Lookup("order_return_rate")
+	require.InDelta(t, 0.5, dataset.Evaluate(orderReturnRate).InexactFloat64(), 1e-9)
+
+	revenue, _ := registry.Lookup("revenue")
+	require.True(t, decimal.NewFromInt(400).Equal(dataset.Evaluate(revenue)))
+
+	withPending := dataset.WithRecognizedPaymentStatuses(reporting.PaymentStatusPaid, reporting.PaymentStatusPending)
+	paidRevenue, _ := registry.Lookup("paid_revenue")
+	require.True(t, decimal.NewFromInt(400).Equal(withPending.Evaluate(paidRevenue)))
+	require.True(t, decimal.NewFromInt(600).Equal(withPending.Evaluate(revenue)))
+
+	reg, err := reporting.LoadMetricRegistryFromJSON(strings.NewReader(
+		`[{"name": "all_gmv", "expression": "item_price", "aggregation": "sum", "include_unrecognized": true}]`))
+	require.NoError(t, err)
+	allGMV, _ := reg.Lookup("all_gmv")
+	require.True(t, decimal.NewFromInt(700).Equal(dataset.Evaluate(allGMV)))
+}
+
+func TestMetricGroupByItem(t *testing.T) {
+	dataset := importTestDataset(t,
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,iPhone 13,storage=128GB,400.00,40.00,400.00,paid,DE,,,Phones",
+		"ORD-2,2025-01-11T10:00:00Z,b@example.com,iPhone 13,storage=256GB,500.00,50.00,0,paid,DE,,,Phones",
+		"ORD-3,2025-01-11T10:00:00Z,c@example.com,Case,,100.00,10.00,0,paid,DE,,,Accessories",
+	)
+	registry := reporting.DefaultMetricRegistry()
+
+	itemReturnRate, _ := registry.Lookup("item_return_rate")
+	rows := dataset.Query(reporting.MetricQuery{Metric: itemReturnRate, GroupBy: reporting.GroupByItem})
+	require.Len(t, rows, 2)
+	require.Equal(t, "Case", rows[0].Group)
+	require.Equal(t, "iPhone 13", rows[1].Group)
+	estimate, ok := itemReturnRate.RateEstimate(rows[1])
+	require.True(t, ok)
+	require.Equal(t, 1, estimate.Successes)
+	require.Equal(t, 2, estimate.Trials)
+	require.InDelta(t, 1.0/2, estimate.Rate(), 1e-9)
+	require.InDelta(t, reporting.NewRateEstimate(1, 2).Lower, estimate.Lower, 1e-9)
+
+	variants := dataset.Query(reporting.MetricQuery{Metric: itemReturnRate, GroupBy: reporting.GroupByItemVariant})
+	require.Len(t, variants, 3)
+	require.Equal(t, "iPhone 13 (storage=128GB)", variants[1].Group)
+
+	netTakeRate, _ := registry.Lookup("net_take_rate")
+	require.InDelta(t, dataset.TotalEarnings().NetTakeRate(), dataset.Evaluate(netTakeRate).InexactFloat64(), 1e-9)
+	_, ok = netTakeRate.RateEstimate(rows[0])
+	require.False(t, ok)
+}
@@ -1,7 +1,6 @@
 package reporting
 
 import (
-	"fmt"
 	"iter"
 	"maps"
 	"slices"
@@ -17,20 +16,28 @@ type Category string
 type OrderID string
 
 type OrderItem struct {
-	OrderID        OrderID
-	NumericOrderID int32
-	OrderedAt      time.Time
-	CustomerEmail  string
-	ItemName       string
-	ItemSpecs      []ItemSpec
-	ItemPrice      decimal.Decimal
-	Commission     decimal.Decimal
-	Refunded       decimal.Decimal
-	PaymentStatus  string
-	Country        string
-	ShippedAt      time.Time
-	DeliveredAt    time.Time
-	Category       []Category
+	OrderID          OrderID
+	NumericOrderID   int32
+	OrderedAt        time.Time
+	CustomerEmail    string
+	ItemName         string
+	ItemSpecs        []ItemSpec
+	ItemPrice        decimal.Decimal
+	Commission       decimal.Decimal
+	Refunded         decimal.Decimal
+	PaymentStatus    PaymentStatus
+	RawPaymentStatus string
+	Country          string
+	ShippedAt        time.Time
+	DeliveredAt      time.Time
+	Category         []Category
+	Currency         Currency
+	Original         OriginalAmounts
+	VATRate          decimal.Decimal
+
+	// businessDaysToDelivery is DeliveredInBusinessDays with the calendar of
+	// the dataset the item was added to.
+	businessDaysToDelivery int
 }
 
 func (r OrderItem) DeliveredIn() time.Duration {
@@ -41,11 +48,23 @@ func (r OrderItem) DeliveredIn() time.Duration {
 	return duration
 }
 
+// DeliveredInBusinessDays counts the business days in the item's country from
+// the order day to the delivery day, see HolidayCalendar.BusinessDaysBetween.
+func (r OrderItem) DeliveredInBusinessDays(cal *HolidayCalendar) int {
+	if r.DeliveredAt.IsZero() {
+		return 0
+	}
+	return cal.BusinessDaysBetween(r.Country, r.OrderedAt, r.DeliveredAt)
+}
+
 type orderItemID = int32
 
 type ItemSpec struct {
 	Key      string
 	RawValue string
+	Kind     SpecKind
+	Value    string
+	Number   float64
 }
 
 type OrderDataset struct {
@@ -53,25 +72,53 @@ type OrderDataset struct {
 	orders            map[OrderID][]OrderItem
 	features          features
 	categories        map[Category]struct{}
+	categoryAncestors map[Category]map[Category]struct{}
 	earliestOrderedAt time.Time
 	latestOrderedAt   time.Time
 
-	totalGross    decimal.Decimal
-	totalRevenue  decimal.Decimal
-	totalReturned int64
+	totalGross      decimal.Decimal
+	totalRevenue    decimal.Decimal
+	totalNet        decimal.Decimal
+	totalNetRevenue decimal.Decimal
+	totalReturned   int64
+
+	totalCommission    decimal.Decimal
+	totalNetCommission decimal.Decimal
 
 	deliveryDurations       []time.Duration
 	sortedDeliveryDurations []time.Duration
+	deliveryBusinessDays    []float64
+
+	reportingCurrency  Currency
+	hasVATRates        bool
+	calendar           *HolidayCalendar
+	recognizedStatuses []PaymentStatus
+	dataQuality        *DataQualityReport
+
+	customerOrders map[string][]OrderID
 }
 
 type features struct {
 	orderCategory     map[Category]*roaring.Bitmap
 	orderItemCategory map[Category]*roaring.Bitmap
 	returned          *roaring.Bitmap
+	recognizedOrder   *roaring.Bitmap
+	recognizedItem    *roaring.Bitmap
+	item              map[string]*roaring.Bitmap
+	itemVariant       map[string]*roaring.Bitmap
+	spec              map[specValue]*roaring.Bitmap
 }
 
 type Order []OrderItem
 
+func newOrderDataset(capacity int) *OrderDataset {
+	return &OrderDataset{
+		allItems:           make([]OrderItem, 0, capacity),
+		orders:             map[OrderID][]OrderItem{},
+		recognizedStatuses: DefaultRecognizedPaymentStatuses(),
+	}
+}
+
 func (ds *OrderDataset) AllItems() iter.Seq[OrderItem] {
 	return slices.Values(ds.allItems)
 }
@@ -93,15 +140,33 @@ func (ds *OrderDataset) add(item OrderItem) {
 	if ds.latestOrderedAt.IsZero() || item.OrderedAt.After(ds.latestOrderedAt) {
 		ds.latestOrderedAt = item.OrderedAt
 	}
+	item.businessDaysToDelivery = item.DeliveredInBusinessDays(ds.calendar)
 	itemID := orderItemID(len(ds.allItems))
 	ds.allItems = append(ds.allItems, item)
 	ds.orders[item.OrderID] = append(ds.orders[item.OrderID], item)
-	ds.totalGross = ds.totalGross.Add(item.ItemPrice)
-	ds.totalRevenue = ds.totalRevenue.Add(item.ItemPrice).Sub(item.Refunded)
 
 	if ds.features.returned == nil {
 		ds.features.returned = roaring.New()
 	}
+	if ds.features.recognizedOrder == nil {
+		ds.features.recognizedOrder = roaring.New()
+	}
+	if ds.features.recognizedItem == nil {
+		ds.features.recognizedItem = roaring.New()
+	}
+	if ds.recognizes(item) {
+		if !ds.features.recognizedOrder.Contains(uint32(item.NumericOrderID)) {
+			ds.addCustomerOrder(item)
+		}
+		ds.features.recognizedOrder.Add(uint32(item.NumericOrderID))
+		ds.features.recognizedItem.Add(uint32(itemID))
+		ds.totalGross = ds.totalGross.Add(item.ItemPrice)
+		ds.totalRevenue = ds.totalRevenue.Add(item.Revenue())
+		ds.totalNet = ds.totalNet.Add(item.NetPrice())
+		ds.totalNetRevenue = ds.totalNetRevenue.Add(item.NetRevenue())
+		ds.totalCommission = ds.totalCommission.Add(item.Commission)
+		ds.totalNetCommission = ds.totalNetCommission.Add(item.NetCommission())
+	}
 	if ds.features.orderCategory == nil {
 		ds.features.orderCategory = map[Category]*roaring.Bitmap{}
 	}
@@ -111,6 +176,9 @@ func (ds *OrderDataset) add(item OrderItem) {
 	if ds.categories == nil {
 		ds.categories = map[Category]struct{}{}
 	}
+	if ds.categoryAncestors == nil {
+		ds.categoryAncestors = map[Category]map[Category]struct{}{}
+	}
 
 	if !item.Refunded.IsZero() {
 		ds.features.returned.Add(uint32(itemID))
@@ -122,8 +190,18 @@ func (ds *OrderDataset) add(item OrderItem) {
 	if ds.features.orderItemCategory == nil {
 		ds.features.orderItemCategory = map[Category]*roaring.Bitmap{}
 	}
-	for _, cat := range item.Category {
+	for i, cat := range item.Category {
 		ds.categories[cat] = struct{}{}
+		if i > 0 {
+			ancestors := ds.categoryAncestors[cat]
+			if ancestors == nil {
+				ancestors = map[Category]struct{}{}
+				ds.categoryAncestors[cat] = ancestors
+			}
+			for _, ancestor := range item.Category[:i] {
+				ancestors[ancestor] = struct{}{}
+			}
+		}
 		orderCategoryBitmap := ds.features.orderCategory[cat]
 		if orderCategoryBitmap == nil {
 			orderCategoryBitmap = roaring.New()
@@ -138,7 +216,51 @@ func (ds *OrderDataset) add(item OrderItem) {
 		}
 		orderItemCategoryBitmap.Add(uint32(itemID))
 	}
-	ds.deliveryDurations = append(ds.deliveryDurations, item.DeliveredIn())
+	ds.addItemIndex(itemID, item)
+	ds.addSpecIndex(itemID, item)
+	if !item.DeliveredAt.IsZero() {
+		ds.deliveryDurations = append(ds.deliveryDurations, item.DeliveredIn())
+		ds.deliveryBusinessDays = append(ds.deliveryBusinessDays, float64(item.businessDaysToDelivery))
+	}
+}
+
+func (ds *OrderDataset) finalize() {
+	deliveryDurations := make([]time.Duration, len(ds.deliveryDurations))
+	copy(deliveryDurations, ds.deliveryDurations)
+
+	sort.Slice(deliveryDurations, func(i, j int) bool {
+		return deliveryDurations[i] < deliveryDurations[j]
+	})
+	ds.sortedDeliveryDurations = deliveryDurations
+	slices.Sort(ds.deliveryBusinessDays)
+}
+
+// derive rebuilds the dataset from its items after configure changed the
+// settings of the copy. mapItem, when set, transforms every item first.
+func (ds *OrderDataset) derive(configure func(derived *OrderDataset), mapItem func(item OrderItem) (OrderItem, error)) (*OrderDataset, error) {
+	derived := newOrderDataset(len(ds.allItems))
+	derived.reportingCurrency = ds.reportingCurrency
+	derived.hasVATRates = ds.hasVATRates
+	derived.calendar = ds.calendar
+	derived.recognizedStatuses = ds.recognizedStatuses
+	derived.dataQuality = ds.dataQuality
+	configure(derived)
+	for _, item := range ds.allItems {
+		if mapItem != nil {
+			var err error
+			item, err = mapItem(item)
+			if err != nil {
+				return nil, err
+			}
+		}
+		derived.add(item)
+	}
+	derived.finalize()
+	return derived, nil
+}
+
+func (ds *OrderDataset) DataQuality() *DataQualityReport {
+	return ds.dataQuality
 }
 
 func (ds *OrderDataset) AllCategories() []Category {
@@ -147,6 +269,11 @@ func (ds *OrderDataset) AllCategories() []Category {
 	return all
 }
 
+func (ds *OrderDataset) isCategoryAncestor(ancestor, cat Category) bool {
+	_, ok := ds.categoryAncestors[cat][ancestor]
+	return ok
+}
+
 func (ds *OrderDataset) DateRange() (earliestOrderedAt, latestOrderedAt time.Time) {
 	return ds.earliestOrderedAt, ds.latestOrderedAt
 }
@@ -157,10 +284,17 @@ func (ds *OrderDataset) NumOrdersByCategory(cat Category) int {
 }
 
 func (ds *OrderDataset) ReturnRateByCategory(cat Category) float64 {
+	return ds.ReturnRateEstimateByCategory(cat).Rate()
+}
+
+func (ds *OrderDataset) ReturnRateEstimateByCategory(cat Category) RateEstimate {
 	allInCategory := ds.features.orderItemCategory[cat]
+	if allInCategory == nil {
+		return NewRateEstimate(0, 0)
+	}
 	returnedInCategory := allInCategory.Clone()
 	returnedInCategory.And(ds.features.returned)
-	return float64(returnedInCategory.GetCardinality()) / float64(allInCategory.GetCardinality())
+	return NewRateEstimate(int(returnedInCategory.GetCardinality()), int(allInCategory.GetCardinality()))
 }
 
 func (ds *OrderDataset) NumOrderItems() int {
@@ -172,7 +306,7 @@ func (ds *OrderDataset) NumOrders() int {
 }
 
 func (ds *OrderDataset) AOV() decimal.Decimal {
-	return ds.totalGross.Div(decimal.NewFromInt(int64(len(ds.orders))))
+	return ds.AOVFor(RevenueBasisGross)
 }
 
 func (ds *OrderDataset) TotalRevenue() decimal.Decimal {
@@ -183,73 +317,97 @@ func (ds *OrderDataset) ReturnRate() decimal.Decimal {
 	return decimal.NewFromInt(ds.totalReturned).Div(decimal.NewFromInt(int64(len(ds.allItems)))).Mul(decimal.NewFromInt(100))
 }
 
+// ReturnRateEstimate is the share of items with a refund.
+func (ds *OrderDataset) ReturnRateEstimate() RateEstimate {
+	return NewRateEstimate(int(ds.totalReturned), len(ds.allItems))
+}
+
+// MedianDelivery is the median delivery time of the delivered items.
+// Undelivered items used to count as a delivery time of zero, which pulled
+// the median down while items were in transit.
 func (ds *OrderDataset) MedianDelivery() time.Duration {
-	l := len(ds.sortedDeliveryDurations)
-	if l == 0 {
+	if len(ds.sortedDeliveryDurations) == 0 {
 		return 0
 	}
-	if l%2 == 0 {
-		return (ds.sortedDeliveryDurations[l/2-1] + ds.sortedDeliveryDurations[l/2]) / 2
+	return medianDuration(ds.sortedDeliveryDurations)
+}
+
+// MedianDeliveryBusinessDays is the median delivery time of the delivered
+// items in business days.
+func (ds *OrderDataset) MedianDeliveryBusinessDays() float64 {
+	return Percentile(ds.deliveryBusinessDays, 50)
+}
+
+// DeliveryPercentile is the p-th percentile of the delivery time of the
+// delivered items.
+func (ds *OrderDataset) DeliveryPercentile(p float64) time.Duration {
+	durations := make([]float64, len(ds.sortedDeliveryDurations))
+	for i, d := range ds.sortedDeliveryDurations {
+		durations[i] = float64(d)
 	}
-	return ds.sortedDeliveryDurations[l/2]
+	return time.Duration(Percentile(durations, p))
+}
+
+// DeliveryBusinessDaysPercentile is the p-th percentile of the delivery time
+// of the delivered items in business days.
+func (ds *OrderDataset) DeliveryBusinessDaysPercentile(p float64) float64 {
+	return Percentile(ds.deliveryBusinessDays, p)
+}
+
+// Calendar returns the holiday calendar delivery times are counted with, nil
+// when the dataset was imported without one.
+func (ds *OrderDataset) Calendar() *HolidayCalendar {
+	return ds.calendar
 }
 
 type IntervalRevenue struct {
-	Start   time.Time
-	End     time.Time
-	Title   string
-	Revenue decimal.Decimal
+	Start    time.Time
+	End      time.Time
+	Title    string
+	Revenue  decimal.Decimal
+	Compared []ComparedValue
 }
 
+func (r IntervalRevenue) bounds() (time.Time, time.Time) { return r.Start, r.End }
+
+func (r IntervalRevenue) headline() decimal.Decimal { return r.Revenue }
+
 func (ds *OrderDataset) RevenueByDay(start, end time.Time) []IntervalRevenue {
-	return ds.revenueByTimeInterval(start, end, 24*time.Hour, func(from, to time.Time) string {
-		return fmt.Sprintf("Day %s", from.Format("2006-01-02"))
-	})
+	return ds.RevenueByDayFor(RevenueBasisGross, start, end)
 }
 
-func (ds *OrderDataset) RevenueByWeek(start, end time.Time) []IntervalRevenue {
-	return ds.revenueByTimeInterval(start, end, 7*24*time.Hour, func(from, to time.Time) string {
-		return fmt.Sprintf("Week %s - %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
-	})
+func (ds *OrderDataset) RevenueByDayFor(basis RevenueBasis, start, end time.Time, comparisons ...Comparison) []IntervalRevenue {
+	return ds.revenueByTimeInterval(start, end, 24*time.Hour, dayTitle, func(item OrderItem) decimal.Decimal {
+		return item.revenue(basis)
+	}, comparisons)
 }
 
-func (ds *OrderDataset) revenueByTimeInterval(start, end time.Time, interval time.Duration, titleFn func(from, to time.Time) string) []IntervalRevenue {
-	intervalGroups := make(map[time.Time]decimal.Decimal)
-	for date := start.Truncate(interval); date.Before(end) || date.Equal(end); date = date.Add(interval) {
-		intervalGroups[date] = decimal.Zero
-	}
+func (ds *OrderDataset) RevenueByWeek(start, end time.Time) []IntervalRevenue {
+	return ds.RevenueByWeekFor(RevenueBasisGross, start, end)
+}
 
-	startTrunc := start.Truncate(interval)
-	endTrunc := end.Truncate(interval)
+func (ds *OrderDataset) RevenueByWeekFor(basis RevenueBasis, start, end time.Time, comparisons ...Comparison) []IntervalRevenue {
+	return ds.revenueByTimeInterval(start, end, 7*24*time.Hour, weekTitle, func(item OrderItem) decimal.Decimal {
+		return item.revenue(basis)
+	}, comparisons)
+}
 
-	for item := range ds.AllItems() {
-		date := item.OrderedAt.Truncate(interval)
-		if dateInInterval(date, startTrunc, endTrunc) {
-			intervalGroups[date] = intervalGroups[date].Add(item.ItemPrice).Sub(item.Refunded)
+func (ds *OrderDataset) revenueByTimeInterval(start, end time.Time, interval time.Duration, titleFn func(from, to time.Time) string, valueFn func(item OrderItem) decimal.Decimal, comparisons []Comparison) []IntervalRevenue {
+	buckets := newIntervalBuckets(start, end, interval, func(from, to time.Time) IntervalRevenue {
+		return IntervalRevenue{Start: from, End: to, Title: titleFn(from, to)}
+	})
+	for item := range ds.recognizedItems() {
+		if r := buckets.at(item.OrderedAt); r != nil {
+			r.Revenue = r.Revenue.Add(valueFn(item))
 		}
 	}
+	res := buckets.buckets
 
-	res := make([]IntervalRevenue, 0, len(intervalGroups))
-	for date, revenue := range intervalGroups {
-		res = append(res, IntervalRevenue{
-			Start:   date,
-			End:     date.Add(interval),
-			Title:   titleFn(date, date.Add(interval)),
-			Revenue: revenue,
-		})
-	}
-	sort.Slice(res, func(i, j int) bool {
-		return res[i].Start.Before(res[j].Start)
+	compared := compareSeries(res, start, end, interval, comparisons, func(start, end time.Time) []IntervalRevenue {
+		return ds.revenueByTimeInterval(start, end, interval, titleFn, valueFn, nil)
 	})
-	return res
-}
-
-func dateInInterval(date time.Time, start, end time.Time) bool {
-	if date.Equal(start) || date.Equal(end) {
-		return true
-	}
-	if date.After(start) && date.Before(end) {
-		return true
+	for i := range res {
+		res[i].Compared = compared[i]
 	}
-	return false
+	return res
 }
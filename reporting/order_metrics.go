@@ -0,0 +1,188 @@
+package reporting
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func (o Order) ID() OrderID {
+	if len(o) == 0 {
+		return ""
+	}
+	return o[0].OrderID
+}
+
+func (o Order) NumItems() int {
+	return len(o)
+}
+
+func (o Order) Gross() decimal.Decimal {
+	total := decimal.Zero
+	for _, item := range o {
+		total = total.Add(item.ItemPrice)
+	}
+	return total
+}
+
+func (o Order) Refunded() decimal.Decimal {
+	total := decimal.Zero
+	for _, item := range o {
+		total = total.Add(item.Refunded)
+	}
+	return total
+}
+
+// AfterRefunds is the gross order value minus refunds. Unlike the net revenue
+// basis it still includes VAT.
+func (o Order) AfterRefunds() decimal.Decimal {
+	return o.Gross().Sub(o.Refunded())
+}
+
+func (o Order) Commission() decimal.Decimal {
+	total := decimal.Zero
+	for _, item := range o {
+		total = total.Add(item.Commission)
+	}
+	return total
+}
+
+func (o Order) AnyReturned() bool {
+	return slices.ContainsFunc(o, func(item OrderItem) bool {
+		return !item.Refunded.IsZero()
+	})
+}
+
+func (o Order) AllReturned() bool {
+	return len(o) > 0 && !slices.ContainsFunc(o, func(item OrderItem) bool {
+		return item.Refunded.IsZero()
+	})
+}
+
+func (o Order) OrderedAt() time.Time {
+	var first time.Time
+	for _, item := range o {
+		if first.IsZero() || item.OrderedAt.Before(first) {
+			first = item.OrderedAt
+		}
+	}
+	return first
+}
+
+// DeliveredAt is the time the last item of the order was delivered. It is zero
+// until every item has been delivered.
+func (o Order) DeliveredAt() time.Time {
+	var last time.Time
+	for _, item := range o {
+		if item.DeliveredAt.IsZero() {
+			return time.Time{}
+		}
+		if item.DeliveredAt.After(last) {
+			last = item.DeliveredAt
+		}
+	}
+	return last
+}
+
+// DeliveredInBusinessDays counts the business days until the last item of the
+// order was delivered.
+func (o Order) DeliveredInBusinessDays(cal *HolidayCalendar) int {
+	deliveredAt := o.DeliveredAt()
+	if deliveredAt.IsZero() {
+		return 0
+	}
+	return cal.BusinessDaysBetween(o[0].Country, o.OrderedAt(), deliveredAt)
+}
+
+func (o Order) DeliveredIn() time.Duration {
+	deliveredAt := o.DeliveredAt()
+	if deliveredAt.IsZero() {
+		return 0
+	}
+	duration := deliveredAt.Sub(o.OrderedAt())
+	if duration <= 0 {
+		return 0
+	}
+	return duration
+}
+
+type OrderOrdering int
+
+const (
+	OrderByID OrderOrdering = iota
+	OrderByOrderedAt
+)
+
+// AllOrdersBy is AllOrders with a deterministic iteration order. Ties in
+// OrderByOrderedAt are broken by order ID.
+func (ds *OrderDataset) AllOrdersBy(ordering OrderOrdering) iter.Seq[Order] {
+	// The keys are computed once, Order.OrderedAt scans all items.
+	type orderKey struct {
+		id        OrderID
+		numericID int32
+		orderedAt time.Time
+	}
+	keys := make([]orderKey, 0, len(ds.orders))
+	for id, order := range ds.orders {
+		key := orderKey{id: id, numericID: order[0].NumericOrderID}
+		if ordering == OrderByOrderedAt {
+			key.orderedAt = Order(order).OrderedAt()
+		}
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b orderKey) int {
+		return cmp.Or(
+			a.orderedAt.Compare(b.orderedAt),
+			cmp.Compare(a.numericID, b.numericID),
+			cmp.Compare(a.id, b.id))
+	})
+	return func(yield func(Order) bool) {
+		for _, key := range keys {
+			if !yield(ds.orders[key.id]) {
+				return
+			}
+		}
+	}
+}
+
+// OrderReturnRate is the share of orders with at least one returned item.
+func (ds *OrderDataset) OrderReturnRate() float64 {
+	return ds.OrderReturnRateEstimate().Rate()
+}
+
+func (ds *OrderDataset) OrderReturnRateEstimate() RateEstimate {
+	returned := 0
+	for order := range ds.AllOrders() {
+		if order.AnyReturned() {
+			returned++
+		}
+	}
+	return NewRateEstimate(returned, len(ds.orders))
+}
+
+// AOVAfterRefunds is the average gross order value after refunds.
+func (ds *OrderDataset) AOVAfterRefunds() decimal.Decimal {
+	return ds.perRecognizedOrder(ds.totalRevenue)
+}
+
+// perRecognizedOrder averages a total over the recognized orders, zero when
+// no order is recognized.
+func (ds *OrderDataset) perRecognizedOrder(total decimal.Decimal) decimal.Decimal {
+	numOrders := ds.numRecognizedOrders()
+	if numOrders == 0 {
+		return decimal.Zero
+	}
+	return total.Div(decimal.NewFromInt(int64(numOrders)))
+}
+
+// numRecognizedOrders counts the orders with at least one item that counts
+// toward revenue.
+func (ds *OrderDataset) numRecognizedOrders() int {
+	if ds.features.recognizedOrder == nil {
+		return 0
+	}
+	return int(ds.features.recognizedOrder.GetCardinality())
+}
@@ -0,0 +1,45 @@
+package reporting_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+
+	"refurbed.com/hackathon/reporting"
+)
+
+func TestOrderMetrics(t *testing.T) {
+	dataset := importTestDataset(t,
+		"ORD-10,2025-01-12T10:00:00Z,a@example.com,iPhone 13,,400.00,40.00,0,paid,DE,2025-01-13T10:00:00Z,2025-01-14T10:00:00Z,Phones",
+		"ORD-10,2025-01-12T10:00:00Z,a@example.com,Case,,100.00,10.00,100.00,paid,DE,2025-01-13T10:00:00Z,2025-01-16T10:00:00Z,Accessories",
+		"ORD-2,2025-01-11T10:00:00Z,b@example.com,iPhone 12,,300.00,30.00,0,paid,AT,2025-01-12T10:00:00Z,,Phones",
+		"ORD-7,2025-01-10T10:00:00Z,c@example.com,iPhone 11,,200.00,20.00,0,paid,AT,,,Phones",
+	)
+
+	var byID []reporting.OrderID
+	for order := range dataset.AllOrdersBy(reporting.OrderByID) {
+		byID = append(byID, order.ID())
+	}
+	require.Equal(t, []reporting.OrderID{"ORD-2", "ORD-7", "ORD-10"}, byID)
+
+	var byOrderedAt []reporting.Order
+	for order := range dataset.AllOrdersBy(reporting.OrderByOrderedAt) {
+		byOrderedAt = append(byOrderedAt, order)
+	}
+	require.Equal(t, reporting.OrderID("ORD-7"), byOrderedAt[0].ID())
+
+	order := byOrderedAt[2]
+	require.Equal(t, 2, order.NumItems())
+	require.True(t, decimal.NewFromInt(500).Equal(order.Gross()))
+	require.True(t, decimal.NewFromInt(400).Equal(order.AfterRefunds()))
+	require.True(t, decimal.NewFromInt(50).Equal(order.Commission()))
+	require.True(t, order.AnyReturned())
+	require.False(t, order.AllReturned())
+	require.Equal(t, 4*24*time.Hour, order.DeliveredIn())
+	require.Zero(t, byOrderedAt[1].DeliveredIn())
+
+	require.InDelta(t, 1.0/3, dataset.OrderReturnRate(), 1e-9)
+	require.True(t, decimal.NewFromInt(300).Equal(dataset.AOVAfterRefunds()))
+}
@@ -0,0 +1,247 @@
+package reporting
+
+import (
+	"iter"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type PaymentStatus int
+
+const (
+	PaymentStatusUnknown PaymentStatus = iota
+	PaymentStatusPending
+	PaymentStatusPaid
+	PaymentStatusFailed
+	PaymentStatusCancelled
+	PaymentStatusRefunded
+)
+
+func (s PaymentStatus) String() string {
+	switch s {
+	case PaymentStatusUnknown:
+		return "unknown"
+	case PaymentStatusPending:
+		return "pending"
+	case PaymentStatusPaid:
+		return "paid"
+	case PaymentStatusFailed:
+		return "failed"
+	case PaymentStatusCancelled:
+		return "cancelled"
+	case PaymentStatusRefunded:
+		return "refunded"
+	default:
+		return "UNKNOWN PAYMENT STATUS"
+	}
+}
+
+func AllPaymentStatuses() []PaymentStatus {
+	return []PaymentStatus{
+		PaymentStatusPaid,
+		PaymentStatusRefunded,
+		PaymentStatusPending,
+		PaymentStatusFailed,
+		PaymentStatusCancelled,
+		PaymentStatusUnknown,
+	}
+}
+
+func ParsePaymentStatus(raw string) PaymentStatus {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "paid", "captured", "completed", "succeeded", "settled":
+		return PaymentStatusPaid
+	case "pending", "authorized", "processing", "open":
+		return PaymentStatusPending
+	case "failed", "declined", "error", "rejected":
+		return PaymentStatusFailed
+	case "cancelled", "canceled", "voided", "expired":
+		return PaymentStatusCancelled
+	case "refunded", "partially_refunded", "chargeback":
+		return PaymentStatusRefunded
+	default:
+		return PaymentStatusUnknown
+	}
+}
+
+// DefaultRecognizedPaymentStatuses only counts paid items toward revenue, so
+// failed, cancelled and pending payments do not inflate it.
+func DefaultRecognizedPaymentStatuses() []PaymentStatus {
+	return PaidRecognizedPaymentStatuses()
+}
+
+// PaidRecognizedPaymentStatuses only counts money that was actually collected.
+func PaidRecognizedPaymentStatuses() []PaymentStatus {
+	return []PaymentStatus{PaymentStatusPaid, PaymentStatusRefunded}
+}
+
+func (ds *OrderDataset) RecognizedPaymentStatuses() []PaymentStatus {
+	return slices.Clone(ds.recognizedStatuses)
+}
+
+// recognizes reports whether the item counts toward revenue metrics. Every
+// report of revenue, prices, commission, customers, refunds and SLA compliance
+// only includes recognized items, and so do metrics unless defined with
+// IncludeUnrecognized. The payment status, return rate, basket and data quality
+// reports include every item, as do the order counts of the heat-map.
+func (ds *OrderDataset) recognizes(item OrderItem) bool {
+	return slices.Contains(ds.recognizedStatuses, item.PaymentStatus)
+}
+
+// recognizedItems yields the items for which recognizes holds.
+func (ds *OrderDataset) recognizedItems() iter.Seq[OrderItem] {
+	return func(yield func(OrderItem) bool) {
+		for _, item := range ds.allItems {
+			if ds.recognizes(item) && !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// WithRecognizedPaymentStatuses returns a copy of the dataset whose revenue
+// metrics only include items with one of the given payment statuses.
+func (ds *OrderDataset) WithRecognizedPaymentStatuses(statuses ...PaymentStatus) *OrderDataset {
+	derived, _ := ds.derive(func(derived *OrderDataset) {
+		derived.recognizedStatuses = slices.Clone(statuses)
+	}, nil)
+	return derived
+}
+
+type PaymentStatusTotals struct {
+	Status PaymentStatus
+	Orders int
+	Items  int
+	Value  decimal.Decimal
+}
+
+type PaymentStatusPeriod struct {
+	Start    time.Time
+	End      time.Time
+	Title    string
+	ByStatus []PaymentStatusTotals
+	// Compared holds the paid orders of the compared periods.
+	Compared []ComparedValue
+}
+
+func (p PaymentStatusPeriod) bounds() (time.Time, time.Time) { return p.Start, p.End }
+
+func (p PaymentStatusPeriod) headline() decimal.Decimal {
+	for _, t := range p.ByStatus {
+		if t.Status == PaymentStatusPaid {
+			return decimal.NewFromInt(int64(t.Orders))
+		}
+	}
+	return decimal.Zero
+}
+
+// newPaymentStatusTotals returns one entry per status, indexed by status.
+func newPaymentStatusTotals() []PaymentStatusTotals {
+	totals := make([]PaymentStatusTotals, len(AllPaymentStatuses()))
+	for i := range totals {
+		totals[i].Status = PaymentStatus(i)
+	}
+	return totals
+}
+
+// addOrderPaymentStatuses counts the order once under every status any of
+// its items has.
+func addOrderPaymentStatuses(totals []PaymentStatusTotals, order Order) {
+	seen := make([]bool, len(totals))
+	for _, item := range order {
+		t := &totals[item.PaymentStatus]
+		t.Items++
+		t.Value = t.Value.Add(item.ItemPrice)
+		if !seen[item.PaymentStatus] {
+			t.Orders++
+			seen[item.PaymentStatus] = true
+		}
+	}
+}
+
+func sortedPaymentStatusTotals(totals []PaymentStatusTotals) []PaymentStatusTotals {
+	res := make([]PaymentStatusTotals, 0, len(totals))
+	for _, status := range AllPaymentStatuses() {
+		res = append(res, totals[status])
+	}
+	return res
+}
+
+// PaymentStatusBreakdown counts orders, items and gross value per payment
+// status. An order with items in several statuses is counted under each.
+func (ds *OrderDataset) PaymentStatusBreakdown() []PaymentStatusTotals {
+	totals := newPaymentStatusTotals()
+	for order := range ds.AllOrders() {
+		addOrderPaymentStatuses(totals, order)
+	}
+	return sortedPaymentStatusTotals(totals)
+}
+
+func (ds *OrderDataset) PaymentStatusByWeek(start, end time.Time, comparisons ...Comparison) []PaymentStatusPeriod {
+	const interval = 7 * 24 * time.Hour
+	buckets := newIntervalBuckets(start, end, interval, func(from, to time.Time) PaymentStatusPeriod {
+		return PaymentStatusPeriod{Start: from, End: to, Title: weekTitle(from, to), ByStatus: newPaymentStatusTotals()}
+	})
+	for order := range ds.AllOrders() {
+		if p := buckets.at(order.OrderedAt()); p != nil {
+			addOrderPaymentStatuses(p.ByStatus, order)
+		}
+	}
+	res := buckets.buckets
+	for i := range res {
+		res[i].ByStatus = sortedPaymentStatusTotals(res[i].ByStatus)
+	}
+
+	compared := compareSeries(res, start, end, interval, comparisons, func(start, end time.Time) []PaymentStatusPeriod {
+		return ds.PaymentStatusByWeek(start, end)
+	})
+	for i := range res {
+		res[i].Compared = compared[i]
+	}
+	return res
+}
+
+type PaymentFunnelStage struct {
+	Name   string
+	Orders int
+	Value  decimal.Decimal
+}
+
+// PaymentFunnel follows orders from placement to payment to being kept. An
+// order is paid when any item is paid or refunded, and kept when any paid item
+// was not refunded.
+func (ds *OrderDataset) PaymentFunnel() []PaymentFunnelStage {
+	stages := []PaymentFunnelStage{{Name: "placed"}, {Name: "paid"}, {Name: "kept"}}
+	for order := range ds.AllOrders() {
+		var paid, kept bool
+		paidValue, keptValue := decimal.Zero, decimal.Zero
+		for _, item := range order {
+			switch item.PaymentStatus {
+			case PaymentStatusPaid:
+				paid = true
+				paidValue = paidValue.Add(item.ItemPrice)
+				if item.Refunded.LessThan(item.ItemPrice) {
+					kept = true
+					keptValue = keptValue.Add(item.Revenue())
+				}
+			case PaymentStatusRefunded:
+				paid = true
+				paidValue = paidValue.Add(item.ItemPrice)
+			}
+		}
+		stages[0].Orders++
+		stages[0].Value = stages[0].Value.Add(order.Gross())
+		if paid {
+			stages[1].Orders++
+			stages[1].Value = stages[1].Value.Add(paidValue)
+		}
+		if kept {
+			stages[2].Orders++
+			stages[2].Value = stages[2].Value.Add(keptValue)
+		}
+	}
+	return stages
+}
@@ -0,0 +1,73 @@
+package reporting_test
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+
+	"refurbed.com/hackathon/reporting"
+)
+
+func TestParsePaymentStatus(t *testing.T) {
+	require.Equal(t, reporting.PaymentStatusPaid, reporting.ParsePaymentStatus(" Captured "))
+	require.Equal(t, reporting.PaymentStatusCancelled, reporting.ParsePaymentStatus("canceled"))
+	require.Equal(t, reporting.PaymentStatusRefunded, reporting.ParsePaymentStatus("chargeback"))
+	require.Equal(t, reporting.PaymentStatusUnknown, reporting.ParsePaymentStatus("lost"))
+}
+
+func TestPaymentStatuses(t *testing.T) {
+	dataset := importTestDataset(t,
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,iPhone 13,,400.00,40.00,0,paid,DE,,,Phones>Smartphones",
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,Case,,100.00,20.00,100.00,refunded,DE,,,Accessories",
+		"ORD-2,2025-01-11T10:00:00Z,b@example.com,iPhone 12,,500.00,40.00,0,failed,AT,,,Phones>Smartphones",
+		"ORD-3,2025-01-12T10:00:00Z,c@example.com,iPhone 11,,300.00,30.00,0,pending,AT,,,Phones>Smartphones",
+	)
+
+	breakdown := dataset.PaymentStatusBreakdown()
+	require.Equal(t, reporting.PaymentStatusPaid, breakdown[0].Status)
+	require.Equal(t, 1, breakdown[0].Orders)
+	require.Equal(t, reporting.PaymentStatusRefunded, breakdown[1].Status)
+	require.True(t, decimal.NewFromInt(100).Equal(breakdown[1].Value))
+
+	funnel := dataset.PaymentFunnel()
+	require.Equal(t, []int{3, 1, 1}, []int{funnel[0].Orders, funnel[1].Orders, funnel[2].Orders})
+	require.True(t, decimal.NewFromInt(500).Equal(funnel[1].Value))
+	require.True(t, decimal.NewFromInt(400).Equal(funnel[2].Value))
+
+	require.True(t, decimal.NewFromInt(400).Equal(dataset.TotalRevenue()))
+	require.True(t, decimal.NewFromInt(500).Equal(dataset.AOV()))
+	require.Equal(t, reporting.PaidRecognizedPaymentStatuses(), dataset.RecognizedPaymentStatuses())
+
+	all := dataset.WithRecognizedPaymentStatuses(reporting.AllPaymentStatuses()...)
+	require.True(t, decimal.NewFromInt(1200).Equal(all.TotalRevenue()))
+	require.Equal(t, 4, all.NumOrderItems())
+}
+
+func TestAOVWithoutRecognizedOrders(t *testing.T) {
+	dataset := importTestDataset(t,
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,iPhone 13,,400.00,40.00,0,paid,DE,,,Phones",
+	)
+
+	cancelled := dataset.WithRecognizedPaymentStatuses(reporting.PaymentStatusCancelled)
+	require.True(t, cancelled.AOVFor(reporting.RevenueBasisGross).IsZero())
+	require.True(t, cancelled.AOVFor(reporting.RevenueBasisNet).IsZero())
+	require.True(t, cancelled.AOVAfterRefunds().IsZero())
+}
+
+func TestReportsOnlyIncludeRecognizedItems(t *testing.T) {
+	dataset := importTestDataset(t,
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,iPhone 13,,400.00,40.00,0,paid,DE,,,Phones",
+		"ORD-2,2025-01-11T10:00:00Z,a@example.com,iPhone 13,,500.00,40.00,0,failed,DE,,,Phones",
+		"ORD-3,2025-01-12T10:00:00Z,b@example.com,iPhone 12,,300.00,30.00,0,cancelled,AT,,,Phones",
+	)
+
+	require.Equal(t, 1, dataset.RefundSummary().Items)
+	require.Len(t, dataset.ItemsNamed("iPhone 13", false), 1)
+	require.Equal(t, 1, dataset.NumCustomers())
+
+	customer, ok := dataset.Customer("a@example.com")
+	require.True(t, ok)
+	require.Equal(t, 1, customer.NumOrders())
+	require.True(t, decimal.NewFromInt(400).Equal(customer.LifetimeValue()))
+}
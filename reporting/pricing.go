@@ -0,0 +1,156 @@
+package reporting
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type PriceSummary struct {
+	Name   string
+	Items  int
+	Min    decimal.Decimal
+	Median decimal.Decimal
+	Max    decimal.Decimal
+}
+
+// Dispersion is the price range relative to the median.
+func (s PriceSummary) Dispersion() float64 {
+	if s.Median.IsZero() {
+		return 0
+	}
+	return s.Max.Sub(s.Min).Div(s.Median).InexactFloat64()
+}
+
+func summarizePrices(name string, prices []decimal.Decimal) PriceSummary {
+	s := PriceSummary{Name: name, Items: len(prices)}
+	if len(prices) == 0 {
+		return s
+	}
+	slices.SortFunc(prices, decimal.Decimal.Cmp)
+	s.Min, s.Max = prices[0], prices[len(prices)-1]
+	l := len(prices)
+	if l%2 == 0 {
+		s.Median = prices[l/2-1].Add(prices[l/2]).Div(decimal.NewFromInt(2))
+	} else {
+		s.Median = prices[l/2]
+	}
+	return s
+}
+
+func itemPrices(items []OrderItem, basis RevenueBasis) []decimal.Decimal {
+	prices := make([]decimal.Decimal, len(items))
+	for i, item := range items {
+		prices[i] = item.price(basis)
+	}
+	return prices
+}
+
+func (ds *OrderDataset) PriceSummary(name string, basis RevenueBasis, withSpecs bool) PriceSummary {
+	return summarizePrices(name, itemPrices(ds.ItemsNamed(name, withSpecs), basis))
+}
+
+// PriceSummaries returns the price summary of every product, the most
+// dispersed first and by name on ties.
+func (ds *OrderDataset) PriceSummaries(basis RevenueBasis, withSpecs bool) []PriceSummary {
+	names := ds.AllItemNames(withSpecs)
+	res := make([]PriceSummary, len(names))
+	for i, name := range names {
+		res[i] = ds.PriceSummary(name, basis, withSpecs)
+	}
+	slices.SortStableFunc(res, func(a, b PriceSummary) int {
+		return cmp.Compare(b.Dispersion(), a.Dispersion())
+	})
+	return res
+}
+
+type PricePoint struct {
+	Start time.Time
+	End   time.Time
+	Title string
+	PriceSummary
+}
+
+// PriceByWeek returns the weekly price summary of a product over the weeks it
+// was ordered in, including weeks without orders in between.
+func (ds *OrderDataset) PriceByWeek(name string, basis RevenueBasis, withSpecs bool) []PricePoint {
+	items := ds.ItemsNamed(name, withSpecs)
+	if len(items) == 0 {
+		return nil
+	}
+
+	first, last := items[0].OrderedAt, items[0].OrderedAt
+	for _, item := range items {
+		if item.OrderedAt.Before(first) {
+			first = item.OrderedAt
+		}
+		if item.OrderedAt.After(last) {
+			last = item.OrderedAt
+		}
+	}
+
+	type weekPrices struct {
+		start, end time.Time
+		prices     []decimal.Decimal
+	}
+	buckets := newIntervalBuckets(first, last, 7*24*time.Hour, func(from, to time.Time) weekPrices {
+		return weekPrices{start: from, end: to}
+	})
+	for _, item := range items {
+		w := buckets.at(item.OrderedAt)
+		w.prices = append(w.prices, item.price(basis))
+	}
+
+	res := make([]PricePoint, len(buckets.buckets))
+	for i, w := range buckets.buckets {
+		res[i] = PricePoint{Start: w.start, End: w.end, Title: weekTitle(w.start, w.end), PriceSummary: summarizePrices(name, w.prices)}
+	}
+	return res
+}
+
+type PriceBand struct {
+	Lower  decimal.Decimal
+	Upper  decimal.Decimal
+	Refund RateEstimate
+}
+
+// RefundRateByPriceBand splits the items of a product into bands holding
+// about the same number of items and returns the share of refunded items per
+// band. Lower is inclusive, Upper too for the last band.
+func (ds *OrderDataset) RefundRateByPriceBand(name string, basis RevenueBasis, withSpecs bool, bands int) []PriceBand {
+	items := ds.ItemsNamed(name, withSpecs)
+	if len(items) == 0 || bands <= 0 {
+		return nil
+	}
+	slices.SortFunc(items, func(a, b OrderItem) int {
+		return a.price(basis).Cmp(b.price(basis))
+	})
+
+	var res []PriceBand
+	start := 0
+	for b := range bands {
+		end := (b + 1) * len(items) / bands
+		// Keep equal prices in one band.
+		for end > start && end < len(items) && items[end].price(basis).Equal(items[end-1].price(basis)) {
+			end++
+		}
+		if end <= start {
+			continue
+		}
+		refunded := 0
+		for _, item := range items[start:end] {
+			if !item.Refunded.IsZero() {
+				refunded++
+			}
+		}
+		res = append(res, PriceBand{
+			Lower:  items[start].price(basis),
+			Upper:  items[end-1].price(basis),
+			Refund: NewRateEstimate(refunded, end-start),
+		})
+		start = end
+	}
+	return res
+}
@@ -0,0 +1,49 @@
+package reporting_test
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+
+	"refurbed.com/hackathon/reporting"
+)
+
+func TestPricing(t *testing.T) {
+	dataset := importTestDataset(t,
+		"ORD-1,2025-01-06T10:00:00Z,a@example.com,iPhone 13,storage=128GB,400.00,40.00,0,paid,DE,,,Phones",
+		"ORD-2,2025-01-07T10:00:00Z,b@example.com,iPhone 13,storage=128GB,420.00,40.00,0,paid,DE,,,Phones",
+		"ORD-3,2025-01-21T10:00:00Z,c@example.com,iPhone 13,storage=128GB,380.00,40.00,380.00,paid,DE,,,Phones",
+		"ORD-4,2025-01-22T10:00:00Z,d@example.com,iPhone 13,storage=256GB,600.00,40.00,0,paid,DE,,,Phones",
+		"ORD-5,2025-01-22T10:00:00Z,e@example.com,MacBook Air,,900.00,40.00,0,paid,DE,,,Laptops",
+		"ORD-6,2025-01-23T10:00:00Z,f@example.com,MacBook Air,,950.00,40.00,0,paid,DE,,,Laptops",
+	)
+
+	summary := dataset.PriceSummary("iPhone 13 (storage=128GB)", reporting.RevenueBasisGross, true)
+	require.Equal(t, 3, summary.Items)
+	require.True(t, decimal.NewFromInt(380).Equal(summary.Min))
+	require.True(t, decimal.NewFromInt(400).Equal(summary.Median))
+	require.True(t, decimal.NewFromInt(420).Equal(summary.Max))
+
+	all := dataset.PriceSummary("iPhone 13", reporting.RevenueBasisGross, false)
+	require.True(t, decimal.NewFromInt(410).Equal(all.Median))
+
+	summaries := dataset.PriceSummaries(reporting.RevenueBasisGross, false)
+	require.Len(t, summaries, 2)
+	require.Equal(t, "iPhone 13", summaries[0].Name)
+	require.Equal(t, "MacBook Air", summaries[1].Name)
+	require.Greater(t, summaries[0].Dispersion(), summaries[1].Dispersion())
+
+	trend := dataset.PriceByWeek("iPhone 13 (storage=128GB)", reporting.RevenueBasisGross, true)
+	require.Len(t, trend, 3)
+	require.Equal(t, 2, trend[0].Items)
+	require.Zero(t, trend[1].Items)
+	require.True(t, decimal.NewFromInt(380).Equal(trend[2].Median))
+
+	bands := dataset.RefundRateByPriceBand("iPhone 13", reporting.RevenueBasisGross, false, 2)
+	require.Len(t, bands, 2)
+	require.True(t, decimal.NewFromInt(380).Equal(bands[0].Lower))
+	require.True(t, decimal.NewFromInt(400).Equal(bands[0].Upper))
+	require.InDelta(t, 0.5, bands[0].Refund.Rate(), 1e-9)
+	require.Zero(t, bands[1].Refund.Successes)
+}
@@ -0,0 +1,145 @@
+package reporting
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/shopspring/decimal"
+)
+
+type RefundKind int
+
+const (
+	RefundNone RefundKind = iota
+	RefundPartial
+	RefundFull
+	RefundOver
+)
+
+func (k RefundKind) String() string {
+	switch k {
+	case RefundNone:
+		return "none"
+	case RefundPartial:
+		return "partial"
+	case RefundFull:
+		return "full"
+	case RefundOver:
+		return "over-refund"
+	default:
+		return "UNKNOWN REFUND KIND"
+	}
+}
+
+func (r OrderItem) RefundKind() RefundKind {
+	switch {
+	case !r.Refunded.IsPositive():
+		return RefundNone
+	case r.Refunded.LessThan(r.ItemPrice):
+		return RefundPartial
+	case r.Refunded.Equal(r.ItemPrice):
+		return RefundFull
+	default:
+		return RefundOver
+	}
+}
+
+type RefundBreakdown struct {
+	Title    string
+	Items    int
+	Partial  int
+	Full     int
+	Over     int
+	Gross    decimal.Decimal
+	Refunded decimal.Decimal
+}
+
+func (b *RefundBreakdown) add(item OrderItem) {
+	b.Items++
+	b.Gross = b.Gross.Add(item.ItemPrice)
+	b.Refunded = b.Refunded.Add(item.Refunded)
+	switch item.RefundKind() {
+	case RefundPartial:
+		b.Partial++
+	case RefundFull:
+		b.Full++
+	case RefundOver:
+		b.Over++
+	}
+}
+
+func (b RefundBreakdown) Refunds() int {
+	return b.Partial + b.Full + b.Over
+}
+
+// RefundCountRate is the share of items with any refund.
+func (b RefundBreakdown) RefundCountRate() float64 {
+	return b.RefundCountEstimate().Rate()
+}
+
+func (b RefundBreakdown) RefundCountEstimate() RateEstimate {
+	return NewRateEstimate(b.Refunds(), b.Items)
+}
+
+// FullRefundCountRate is the share of items refunded in full or more.
+func (b RefundBreakdown) FullRefundCountRate() float64 {
+	return b.FullRefundCountEstimate().Rate()
+}
+
+func (b RefundBreakdown) FullRefundCountEstimate() RateEstimate {
+	return NewRateEstimate(b.Full+b.Over, b.Items)
+}
+
+// RefundValueRate is the refunded amount divided by the gross item value.
+func (b RefundBreakdown) RefundValueRate() float64 {
+	if b.Gross.IsZero() {
+		return 0
+	}
+	return b.Refunded.Div(b.Gross).InexactFloat64()
+}
+
+// RefundValueEstimate is the refund value rate as a share estimate over the
+// items. Over-refunds are capped at a rate of 1.
+func (b RefundBreakdown) RefundValueEstimate() RateEstimate {
+	return NewShareEstimate(b.RefundValueRate(), b.Items)
+}
+
+func (ds *OrderDataset) RefundSummary() RefundBreakdown {
+	b := RefundBreakdown{Title: "Total"}
+	for item := range ds.recognizedItems() {
+		b.add(item)
+	}
+	return b
+}
+
+func (ds *OrderDataset) RefundsByCategory() []RefundBreakdown {
+	res := make([]RefundBreakdown, 0, len(ds.categories))
+	for _, cat := range ds.AllCategories() {
+		b := RefundBreakdown{Title: string(cat)}
+		ds.features.orderItemCategory[cat].Iterate(func(itemID uint32) bool {
+			if item := ds.allItems[itemID]; ds.recognizes(item) {
+				b.add(item)
+			}
+			return true
+		})
+		res = append(res, b)
+	}
+	return res
+}
+
+func (ds *OrderDataset) RefundsByCountry() []RefundBreakdown {
+	byCountry := map[string]*RefundBreakdown{}
+	for item := range ds.recognizedItems() {
+		b := byCountry[item.Country]
+		if b == nil {
+			b = &RefundBreakdown{Title: item.Country}
+			byCountry[item.Country] = b
+		}
+		b.add(item)
+	}
+	res := make([]RefundBreakdown, 0, len(byCountry))
+	for _, country := range slices.Sorted(maps.Keys(byCountry)) {
+		res = append(res, *byCountry[country])
+	}
+	return res
+}
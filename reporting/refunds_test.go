@@ -0,0 +1,44 @@
+package reporting_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"refurbed.com/hackathon/reporting"
+)
+
+func TestRefundSummary(t *testing.T) {
+	dataset := importTestDataset(t,
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,iPhone 13,,400.00,40.00,0,paid,DE,,,Phones",
+		"ORD-2,2025-01-10T10:00:00Z,b@example.com,iPhone 12,,300.00,30.00,5.00,paid,DE,,,Phones",
+		"ORD-3,2025-01-10T10:00:00Z,c@example.com,iPhone 11,,200.00,20.00,200.00,paid,AT,,,Phones",
+		"ORD-4,2025-01-10T10:00:00Z,d@example.com,Case,,100.00,10.00,195.00,paid,AT,,,Accessories",
+	)
+
+	summary := dataset.RefundSummary()
+	require.Equal(t, 4, summary.Items)
+	require.Equal(t, 1, summary.Partial)
+	require.Equal(t, 1, summary.Full)
+	require.Equal(t, 1, summary.Over)
+	require.InDelta(t, 0.75, summary.RefundCountRate(), 1e-9)
+	require.InDelta(t, 0.5, summary.FullRefundCountRate(), 1e-9)
+	require.InDelta(t, 0.4, summary.RefundValueRate(), 1e-9)
+	require.Equal(t, 2, summary.FullRefundCountEstimate().Successes)
+	value := summary.RefundValueEstimate()
+	require.InDelta(t, 0.4, value.Rate(), 1e-9)
+	require.Equal(t, 4, value.Trials)
+	require.Less(t, value.Lower, 0.4)
+	require.Greater(t, value.Upper, 0.4)
+
+	byCountry := dataset.RefundsByCountry()
+	require.Len(t, byCountry, 2)
+	require.Equal(t, "DE", byCountry[1].Title)
+	require.InDelta(t, 5.0/700, byCountry[1].RefundValueRate(), 1e-9)
+
+	var kinds []reporting.RefundKind
+	for item := range dataset.AllItems() {
+		kinds = append(kinds, item.RefundKind())
+	}
+	require.Equal(t, []reporting.RefundKind{reporting.RefundNone, reporting.RefundPartial, reporting.RefundFull, reporting.RefundOver}, kinds)
+}
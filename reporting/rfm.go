@@ -0,0 +1,183 @@
+package reporting
+
+import (
+	"cmp"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RFMSegment names customers whose normalized scores match. Scores passed to
+// Match are in [0, 1], where 0 is the worst quantile and 1 the best.
+type RFMSegment struct {
+	Name  string
+	Match func(recency, frequency, monetary float64) bool
+}
+
+type RFMConfig struct {
+	Quantiles     int
+	ReferenceDate time.Time
+	Segments      []RFMSegment
+}
+
+func DefaultRFMSegments() []RFMSegment {
+	return []RFMSegment{
+		{Name: "champions", Match: func(r, f, m float64) bool { return r >= 0.75 && f >= 0.75 }},
+		{Name: "loyal customers", Match: func(r, f, m float64) bool { return r >= 0.5 && f >= 0.5 }},
+		{Name: "new customers", Match: func(r, f, m float64) bool { return r >= 0.75 && f <= 0.25 }},
+		{Name: "potential loyalists", Match: func(r, f, m float64) bool { return r >= 0.5 }},
+		{Name: "at risk", Match: func(r, f, m float64) bool { return r <= 0.25 && f >= 0.5 }},
+		{Name: "lost", Match: func(r, f, m float64) bool { return r == 0 && f <= 0.25 }},
+		{Name: "hibernating", Match: func(r, f, m float64) bool { return r <= 0.25 }},
+		{Name: "need attention", Match: func(r, f, m float64) bool { return true }},
+	}
+}
+
+// DefaultRFMConfig scores in quintiles relative to the last order in the
+// dataset.
+func (ds *OrderDataset) DefaultRFMConfig() RFMConfig {
+	return RFMConfig{
+		Quantiles:     5,
+		ReferenceDate: ds.latestOrderedAt,
+		Segments:      DefaultRFMSegments(),
+	}
+}
+
+type RFMScore struct {
+	Email       string
+	RecencyDays int
+	Frequency   int
+	Monetary    decimal.Decimal
+	R           int
+	F           int
+	M           int
+	Segment     string
+}
+
+func (ds *OrderDataset) RFMScores(cfg RFMConfig) []RFMScore {
+	if cfg.Quantiles <= 0 {
+		cfg.Quantiles = 5
+	}
+	scores := make([]RFMScore, 0, ds.NumCustomers())
+	for c := range ds.Customers() {
+		scores = append(scores, RFMScore{
+			Email:       c.Email,
+			RecencyDays: int(cfg.ReferenceDate.Sub(c.LastOrderedAt()).Hours() / 24),
+			Frequency:   c.NumOrders(),
+			Monetary:    c.LifetimeValue(),
+		})
+	}
+
+	assignQuantileScores(scores, cfg.Quantiles, func(a, b RFMScore) int {
+		return cmp.Compare(b.RecencyDays, a.RecencyDays)
+	}, func(s *RFMScore, score int) { s.R = score })
+	assignQuantileScores(scores, cfg.Quantiles, func(a, b RFMScore) int {
+		return cmp.Compare(a.Frequency, b.Frequency)
+	}, func(s *RFMScore, score int) { s.F = score })
+	assignQuantileScores(scores, cfg.Quantiles, func(a, b RFMScore) int {
+		return a.Monetary.Cmp(b.Monetary)
+	}, func(s *RFMScore, score int) { s.M = score })
+
+	normalize := func(score int) float64 {
+		if cfg.Quantiles == 1 {
+			return 1
+		}
+		return float64(score-1) / float64(cfg.Quantiles-1)
+	}
+	for i := range scores {
+		s := &scores[i]
+		for _, segment := range cfg.Segments {
+			if segment.Match(normalize(s.R), normalize(s.F), normalize(s.M)) {
+				s.Segment = segment.Name
+				break
+			}
+		}
+	}
+	slices.SortFunc(scores, func(a, b RFMScore) int {
+		return cmp.Compare(a.Email, b.Email)
+	})
+	return scores
+}
+
+// assignQuantileScores ranks scores from worst to best with compare and gives
+// each a score between 1 and quantiles. Equal values share the score of their
+// lowest rank so ties never straddle two quantiles.
+func assignQuantileScores(scores []RFMScore, quantiles int, compare func(a, b RFMScore) int, set func(s *RFMScore, score int)) {
+	slices.SortStableFunc(scores, compare)
+	n := len(scores)
+	tieStart := 0
+	for i := range scores {
+		if i > 0 && compare(scores[i-1], scores[i]) != 0 {
+			tieStart = i
+		}
+		set(&scores[i], tieStart*quantiles/n+1)
+	}
+}
+
+type RFMSegmentSummary struct {
+	Name          string
+	Customers     int
+	Revenue       decimal.Decimal
+	CustomerShare float64
+	RevenueShare  float64
+}
+
+// SummarizeRFMSegments returns the size and revenue of every segment in the
+// order the segments are declared.
+func SummarizeRFMSegments(scores []RFMScore, segments []RFMSegment) []RFMSegmentSummary {
+	bySegment := map[string]*RFMSegmentSummary{}
+	res := make([]RFMSegmentSummary, len(segments))
+	for i, segment := range segments {
+		res[i].Name = segment.Name
+		bySegment[segment.Name] = &res[i]
+	}
+
+	total := decimal.Zero
+	for _, s := range scores {
+		summary := bySegment[s.Segment]
+		if summary == nil {
+			continue
+		}
+		summary.Customers++
+		summary.Revenue = summary.Revenue.Add(s.Monetary)
+		total = total.Add(s.Monetary)
+	}
+	for i := range res {
+		if len(scores) > 0 {
+			res[i].CustomerShare = float64(res[i].Customers) / float64(len(scores))
+		}
+		if !total.IsZero() {
+			res[i].RevenueShare = res[i].Revenue.Div(total).InexactFloat64()
+		}
+	}
+	return res
+}
+
+func WriteRFMScoresCSV(w io.Writer, scores []RFMScore) error {
+	csvw := csv.NewWriter(w)
+	err := csvw.Write([]string{"customer_email", "segment", "recency_days", "frequency", "monetary", "r", "f", "m"})
+	if err != nil {
+		return fmt.Errorf("write CSV header: %w", err)
+	}
+	for _, s := range scores {
+		err := csvw.Write([]string{
+			s.Email,
+			s.Segment,
+			fmt.Sprint(s.RecencyDays),
+			fmt.Sprint(s.Frequency),
+			s.Monetary.StringFixed(2),
+			fmt.Sprint(s.R),
+			fmt.Sprint(s.F),
+			fmt.Sprint(s.M),
+		})
+		if err != nil {
+			return fmt.Errorf("write CSV row: %w", err)
+		}
+	}
+	csvw.Flush()
+	return csvw.Error()
+}
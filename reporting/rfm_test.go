@@ -0,0 +1,47 @@
+package reporting_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"refurbed.com/hackathon/reporting"
+)
+
+func TestRFMScores(t *testing.T) {
+	dataset := importTestDataset(t,
+		"ORD-1,2025-01-01T10:00:00Z,lost@example.com,iPhone 11,,100.00,10.00,0,paid,DE,,,Phones",
+		"ORD-2,2025-01-02T10:00:00Z,risk@example.com,iPhone 12,,300.00,30.00,0,paid,DE,,,Phones",
+		"ORD-3,2025-01-03T10:00:00Z,risk@example.com,iPhone 12,,300.00,30.00,0,paid,DE,,,Phones",
+		"ORD-4,2025-02-27T10:00:00Z,champ@example.com,iPhone 13,,500.00,50.00,0,paid,DE,,,Phones",
+		"ORD-5,2025-02-28T10:00:00Z,champ@example.com,iPhone 13,,500.00,50.00,0,paid,DE,,,Phones",
+		"ORD-6,2025-02-28T10:00:00Z,new@example.com,Case,,20.00,2.00,0,paid,DE,,,Accessories",
+	)
+
+	cfg := dataset.DefaultRFMConfig()
+	cfg.Quantiles = 2
+	scores := dataset.RFMScores(cfg)
+	require.Len(t, scores, 4)
+
+	segments := map[string]string{}
+	for _, s := range scores {
+		segments[s.Email] = s.Segment
+	}
+	require.Equal(t, "champions", segments["champ@example.com"])
+	require.Equal(t, "new customers", segments["new@example.com"])
+	require.Equal(t, "at risk", segments["risk@example.com"])
+	require.Equal(t, "lost", segments["lost@example.com"])
+
+	summary := reporting.SummarizeRFMSegments(scores, cfg.Segments)
+	require.Equal(t, "champions", summary[0].Name)
+	require.Equal(t, 1, summary[0].Customers)
+	require.InDelta(t, 1000.0/1720, summary[0].RevenueShare, 1e-9)
+
+	var out bytes.Buffer
+	require.NoError(t, reporting.WriteRFMScoresCSV(&out, scores))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 5)
+	require.Equal(t, "champ@example.com,champions,0,2,1000.00,2,2,2", lines[1])
+}
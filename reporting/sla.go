@@ -0,0 +1,260 @@
+package reporting
+
+import (
+	"cmp"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SLATarget is the promise made to customers in business days after the order
+// day. A zero ShipDays makes no promise about the handover to the carrier.
+type SLATarget struct {
+	ShipDays    int
+	DeliverDays int
+}
+
+type SLAConfig struct {
+	Default   SLATarget
+	ByCountry map[string]SLATarget
+}
+
+func DefaultSLAConfig() SLAConfig {
+	return SLAConfig{
+		Default:   SLATarget{ShipDays: 2, DeliverDays: 5},
+		ByCountry: map[string]SLATarget{},
+	}
+}
+
+// LoadSLAConfigFromCSV reads a table with the columns country, ship_days and
+// deliver_days. The country "*" sets the default of unlisted countries.
+func LoadSLAConfigFromCSV(r io.Reader) (SLAConfig, error) {
+	csvr := csv.NewReader(r)
+
+	headerFields, err := csvr.Read()
+	if err != nil {
+		return SLAConfig{}, fmt.Errorf("unexpected I/O error before CSV header: %w", err)
+	}
+	countryIdx := slices.Index(headerFields, "country")
+	shipIdx := slices.Index(headerFields, "ship_days")
+	deliverIdx := slices.Index(headerFields, "deliver_days")
+	if countryIdx == -1 || shipIdx == -1 || deliverIdx == -1 {
+		return SLAConfig{}, fmt.Errorf("SLA header %q must contain country, ship_days and deliver_days", headerFields)
+	}
+
+	cfg := DefaultSLAConfig()
+	for {
+		fields, err := csvr.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return SLAConfig{}, fmt.Errorf("read CSV row: %w", err)
+		}
+		shipDays, err := strconv.Atoi(fields[shipIdx])
+		if err != nil {
+			return SLAConfig{}, fmt.Errorf("parse ship_days of %s: %w", fields[countryIdx], err)
+		}
+		deliverDays, err := strconv.Atoi(fields[deliverIdx])
+		if err != nil {
+			return SLAConfig{}, fmt.Errorf("parse deliver_days of %s: %w", fields[countryIdx], err)
+		}
+		target := SLATarget{ShipDays: shipDays, DeliverDays: deliverDays}
+		if fields[countryIdx] == "*" {
+			cfg.Default = target
+		} else {
+			cfg.ByCountry[strings.ToUpper(fields[countryIdx])] = target
+		}
+	}
+	return cfg, nil
+}
+
+func (c SLAConfig) TargetFor(country string) SLATarget {
+	if t, ok := c.ByCountry[strings.ToUpper(country)]; ok {
+		return t
+	}
+	return c.Default
+}
+
+type SLAStatus int
+
+const (
+	// SLAPending items are not delivered yet but still within their deadline.
+	SLAPending SLAStatus = iota
+	SLAMet
+	SLABreached
+)
+
+func (s SLAStatus) String() string {
+	switch s {
+	case SLAPending:
+		return "pending"
+	case SLAMet:
+		return "met"
+	case SLABreached:
+		return "breached"
+	default:
+		return "UNKNOWN SLA STATUS"
+	}
+}
+
+type SLAResult struct {
+	Status   SLAStatus
+	Deadline time.Time
+	// BusinessDays is the delivery time, or the time so far when the item was
+	// not delivered yet.
+	BusinessDays int
+	ShippedLate  bool
+}
+
+// ClassifySLA checks an item against its country's target as of the given
+// time, counting business days with the calendar. An item delivered on its
+// deadline day meets the SLA. A delivered item without a ship date is taken to
+// have shipped on its delivery day.
+func (c SLAConfig) ClassifySLA(item OrderItem, cal *HolidayCalendar, asOf time.Time) SLAResult {
+	target := c.TargetFor(item.Country)
+	res := SLAResult{Deadline: cal.AddBusinessDays(item.Country, item.OrderedAt, target.DeliverDays)}
+
+	if target.ShipDays > 0 {
+		shipDeadline := cal.AddBusinessDays(item.Country, item.OrderedAt, target.ShipDays)
+		shippedAt := cmp.Or(item.ShippedAt, item.DeliveredAt, asOf)
+		res.ShippedLate = dayOf(shippedAt).After(shipDeadline)
+	}
+
+	switch {
+	case !item.DeliveredAt.IsZero():
+		res.BusinessDays = cal.BusinessDaysBetween(item.Country, item.OrderedAt, item.DeliveredAt)
+		res.Status = SLAMet
+		if dayOf(item.DeliveredAt).After(res.Deadline) {
+			res.Status = SLABreached
+		}
+	case dayOf(asOf).After(res.Deadline):
+		res.BusinessDays = cal.BusinessDaysBetween(item.Country, item.OrderedAt, asOf)
+		res.Status = SLABreached
+	default:
+		res.BusinessDays = cal.BusinessDaysBetween(item.Country, item.OrderedAt, asOf)
+		res.Status = SLAPending
+	}
+	return res
+}
+
+type SLACompliance struct {
+	Title       string
+	Met         int
+	Breached    int
+	Pending     int
+	ShippedLate int
+	// Start and End are only set on weekly compliance, where Compared holds
+	// the compliance rate of the compared periods.
+	Start    time.Time
+	End      time.Time
+	Compared []ComparedValue
+}
+
+func (c SLACompliance) bounds() (time.Time, time.Time) { return c.Start, c.End }
+
+func (c SLACompliance) headline() decimal.Decimal { return decimal.NewFromFloat(c.Rate().Rate()) }
+
+func (c *SLACompliance) add(res SLAResult) {
+	switch res.Status {
+	case SLAMet:
+		c.Met++
+	case SLABreached:
+		c.Breached++
+	default:
+		c.Pending++
+	}
+	if res.ShippedLate {
+		c.ShippedLate++
+	}
+}
+
+// Rate is the share of decided items that met the SLA. Pending items are left
+// out.
+func (c SLACompliance) Rate() RateEstimate {
+	return NewRateEstimate(c.Met, c.Met+c.Breached)
+}
+
+// slaAsOf is the moment the SLA of undelivered items is judged at, the last
+// order of the dataset, so that an old export does not count as breached.
+func (ds *OrderDataset) slaAsOf() time.Time {
+	return ds.latestOrderedAt
+}
+
+// SLACompliance classifies the recognized items, counting business days with
+// the dataset's holiday calendar.
+func (ds *OrderDataset) SLACompliance(cfg SLAConfig) SLACompliance {
+	total := SLACompliance{Title: "Total"}
+	asOf := ds.slaAsOf()
+	for item := range ds.recognizedItems() {
+		total.add(cfg.ClassifySLA(item, ds.calendar, asOf))
+	}
+	return total
+}
+
+func (ds *OrderDataset) slaComplianceBy(cfg SLAConfig, keys func(item OrderItem) []string) []SLACompliance {
+	groups := map[string]*SLACompliance{}
+	asOf := ds.slaAsOf()
+	for item := range ds.recognizedItems() {
+		res := cfg.ClassifySLA(item, ds.calendar, asOf)
+		for _, key := range keys(item) {
+			if groups[key] == nil {
+				groups[key] = &SLACompliance{Title: key}
+			}
+			groups[key].add(res)
+		}
+	}
+	res := make([]SLACompliance, 0, len(groups))
+	for _, key := range slices.Sorted(maps.Keys(groups)) {
+		res = append(res, *groups[key])
+	}
+	return res
+}
+
+func (ds *OrderDataset) SLAComplianceByCountry(cfg SLAConfig) []SLACompliance {
+	return ds.slaComplianceBy(cfg, func(item OrderItem) []string {
+		return []string{item.Country}
+	})
+}
+
+// SLAComplianceByCategory counts an item toward every category on its path.
+func (ds *OrderDataset) SLAComplianceByCategory(cfg SLAConfig) []SLACompliance {
+	return ds.slaComplianceBy(cfg, func(item OrderItem) []string {
+		keys := make([]string, len(item.Category))
+		for i, c := range item.Category {
+			keys[i] = string(c)
+		}
+		return keys
+	})
+}
+
+// SLAComplianceByWeek groups the items by the week they were ordered in.
+func (ds *OrderDataset) SLAComplianceByWeek(cfg SLAConfig, start, end time.Time, comparisons ...Comparison) []SLACompliance {
+	const interval = 7 * 24 * time.Hour
+	buckets := newIntervalBuckets(start, end, interval, func(from, to time.Time) SLACompliance {
+		return SLACompliance{Title: weekTitle(from, to), Start: from, End: to}
+	})
+	asOf := ds.slaAsOf()
+	for item := range ds.recognizedItems() {
+		if c := buckets.at(item.OrderedAt); c != nil {
+			c.add(cfg.ClassifySLA(item, ds.calendar, asOf))
+		}
+	}
+	res := buckets.buckets
+
+	compared := compareSeries(res, start, end, interval, comparisons, func(start, end time.Time) []SLACompliance {
+		return ds.SLAComplianceByWeek(cfg, start, end)
+	})
+	for i := range res {
+		res[i].Compared = compared[i]
+	}
+	return res
+}
@@ -0,0 +1,58 @@
+package reporting_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"refurbed.com/hackathon/reporting"
+)
+
+func TestBusinessDaysBetween(t *testing.T) {
+	cal, err := reporting.LoadHolidaysFromCSV(strings.NewReader("country,date,name\nDE,2025-01-13,Test holiday\n"))
+	require.NoError(t, err)
+
+	friday := time.Date(2025, 1, 10, 18, 0, 0, 0, time.UTC)
+	wednesday := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+	require.Equal(t, 2, cal.BusinessDaysBetween("DE", friday, wednesday))
+	require.Equal(t, 3, cal.BusinessDaysBetween("FR", friday, wednesday))
+
+	var noHolidays *reporting.HolidayCalendar
+	require.Equal(t, 3, noHolidays.BusinessDaysBetween("DE", friday, wednesday))
+	require.Equal(t, time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), cal.AddBusinessDays("de", friday, 2))
+}
+
+func TestSLACompliance(t *testing.T) {
+	cfg, err := reporting.LoadSLAConfigFromCSV(strings.NewReader("country,ship_days,deliver_days\n*,2,5\nDE,1,2\n"))
+	require.NoError(t, err)
+	cal, err := reporting.LoadHolidaysFromCSV(strings.NewReader("country,date,name\nDE,2025-01-13,Test holiday\n"))
+	require.NoError(t, err)
+
+	in := strings.NewReader(testCSVHeader + "\n" + strings.Join([]string{
+		"ORD-1,2025-01-10T18:00:00Z,a@example.com,iPhone 13,,400.00,40.00,0,paid,DE,2025-01-14T10:00:00Z,2025-01-15T10:00:00Z,Phones",
+		"ORD-2,2025-01-10T18:00:00Z,b@example.com,iPhone 13,,400.00,40.00,0,paid,DE,2025-01-15T10:00:00Z,2025-01-16T10:00:00Z,Phones",
+		"ORD-3,2025-01-20T10:00:00Z,c@example.com,iPhone 12,,300.00,30.00,0,paid,FR,,,Phones",
+		"ORD-4,2025-01-06T10:00:00Z,d@example.com,MacBook Air,,900.00,90.00,0,paid,FR,,,Laptops",
+		"ORD-5,2025-01-06T10:00:00Z,e@example.com,MacBook Air,,900.00,90.00,0,failed,FR,,,Laptops",
+		"ORD-6,2025-01-13T10:00:00Z,f@example.com,iPhone 12,,300.00,30.00,0,paid,FR,,2025-01-14T10:00:00Z,Phones",
+	}, "\n") + "\n")
+	dataset, err := reporting.ImportOrderDatasetFromCSV(in, reporting.WithHolidayCalendar(cal))
+	require.NoError(t, err)
+
+	total := dataset.SLACompliance(cfg)
+	require.Equal(t, reporting.SLACompliance{Title: "Total", Met: 2, Breached: 2, Pending: 1, ShippedLate: 2}, total)
+	require.Equal(t, 4, total.Rate().Trials)
+
+	byCountry := dataset.SLAComplianceByCountry(cfg)
+	require.Equal(t, []reporting.SLACompliance{
+		{Title: "DE", Met: 1, Breached: 1, ShippedLate: 1},
+		{Title: "FR", Met: 1, Breached: 1, Pending: 1, ShippedLate: 1},
+	}, byCountry)
+
+	byCategory := dataset.SLAComplianceByCategory(cfg)
+	require.Len(t, byCategory, 2)
+	require.Equal(t, "Laptops", byCategory[0].Title)
+	require.Equal(t, 1, byCategory[0].Breached)
+}
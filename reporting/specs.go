@@ -0,0 +1,240 @@
+package reporting
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/shopspring/decimal"
+)
+
+type SpecKind int
+
+const (
+	SpecKindText SpecKind = iota
+	SpecKindStorage
+	SpecKindColour
+	SpecKindGrade
+	SpecKindBattery
+)
+
+func (k SpecKind) String() string {
+	switch k {
+	case SpecKindText:
+		return "text"
+	case SpecKindStorage:
+		return "storage"
+	case SpecKindColour:
+		return "colour"
+	case SpecKindGrade:
+		return "grade"
+	case SpecKindBattery:
+		return "battery"
+	default:
+		return "UNKNOWN SPEC KIND"
+	}
+}
+
+// Canonical keys of the typed specs. Aliases such as "color" or "condition"
+// are mapped to them on import.
+const (
+	SpecKeyStorage = "storage"
+	SpecKeyColour  = "colour"
+	SpecKeyGrade   = "grade"
+	SpecKeyBattery = "battery"
+)
+
+var specKeyAliases = map[string]string{
+	"storage":        SpecKeyStorage,
+	"capacity":       SpecKeyStorage,
+	"storage_size":   SpecKeyStorage,
+	"colour":         SpecKeyColour,
+	"color":          SpecKeyColour,
+	"grade":          SpecKeyGrade,
+	"condition":      SpecKeyGrade,
+	"battery":        SpecKeyBattery,
+	"battery_health": SpecKeyBattery,
+}
+
+var storageUnits = map[string]int64{
+	"mb": 1 << 20,
+	"gb": 1 << 30,
+	"tb": 1 << 40,
+}
+
+var conditionGrades = map[string]string{
+	"a":         "A",
+	"excellent": "A",
+	"b":         "B",
+	"very good": "B",
+	"c":         "C",
+	"good":      "C",
+}
+
+// parseItemSpec normalizes a spec. Storage sizes keep their size in bytes and
+// battery health its percentage in Number. Values that do not parse stay text
+// specs with their raw value, but aliased keys are always canonical.
+func parseItemSpec(key, rawValue string) ItemSpec {
+	key = strings.ToLower(strings.TrimSpace(key))
+	spec := ItemSpec{Key: key, RawValue: rawValue, Value: strings.TrimSpace(rawValue)}
+	canonical, ok := specKeyAliases[key]
+	if !ok {
+		return spec
+	}
+	spec.Key = canonical
+
+	value := strings.ToLower(strings.Join(strings.Fields(rawValue), " "))
+	switch canonical {
+	case SpecKeyStorage:
+		compact := strings.ReplaceAll(value, " ", "")
+		compact = strings.TrimSuffix(compact, "b") + "b"
+		for unit, size := range storageUnits {
+			n, found := strings.CutSuffix(compact, unit)
+			if !found {
+				continue
+			}
+			amount, err := strconv.ParseFloat(n, 64)
+			if err != nil || amount <= 0 {
+				return spec
+			}
+			return ItemSpec{Key: canonical, RawValue: rawValue, Kind: SpecKindStorage, Value: formatStorage(int64(amount * float64(size))), Number: amount * float64(size)}
+		}
+	case SpecKeyColour:
+		value = strings.NewReplacer("-", " ", "_", " ", "gray", "grey").Replace(value)
+		if value == "" {
+			return spec
+		}
+		return ItemSpec{Key: canonical, RawValue: rawValue, Kind: SpecKindColour, Value: value}
+	case SpecKeyGrade:
+		grade, ok := conditionGrades[strings.TrimPrefix(value, "grade ")]
+		if !ok {
+			return spec
+		}
+		return ItemSpec{Key: canonical, RawValue: rawValue, Kind: SpecKindGrade, Value: grade}
+	case SpecKeyBattery:
+		percent, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(value, "%")), 64)
+		if err != nil || percent < 0 || percent > 100 {
+			return spec
+		}
+		if !strings.HasSuffix(value, "%") && percent <= 1 {
+			percent *= 100
+		}
+		return ItemSpec{Key: canonical, RawValue: rawValue, Kind: SpecKindBattery, Value: fmt.Sprintf("%.0f%%", percent), Number: percent}
+	}
+	return spec
+}
+
+func formatStorage(bytes int64) string {
+	switch {
+	case bytes >= 1<<40 && bytes%(1<<40) == 0:
+		return fmt.Sprintf("%dTB", bytes>>40)
+	case bytes >= 1<<30:
+		return strconv.FormatFloat(float64(bytes)/(1<<30), 'f', -1, 64) + "GB"
+	default:
+		return strconv.FormatFloat(float64(bytes)/(1<<20), 'f', -1, 64) + "MB"
+	}
+}
+
+// Spec returns the first spec of the item with the given canonical key.
+func (r OrderItem) Spec(key string) (ItemSpec, bool) {
+	for _, spec := range r.ItemSpecs {
+		if spec.Key == key {
+			return spec, true
+		}
+	}
+	return ItemSpec{}, false
+}
+
+type specValue struct {
+	Key   string
+	Value string
+}
+
+func (ds *OrderDataset) addSpecIndex(itemID orderItemID, item OrderItem) {
+	if ds.features.spec == nil {
+		ds.features.spec = map[specValue]*roaring.Bitmap{}
+	}
+	for _, spec := range item.ItemSpecs {
+		sv := specValue{Key: spec.Key, Value: spec.Value}
+		bitmap := ds.features.spec[sv]
+		if bitmap == nil {
+			bitmap = roaring.New()
+			ds.features.spec[sv] = bitmap
+		}
+		bitmap.Add(uint32(itemID))
+	}
+}
+
+func (ds *OrderDataset) AllSpecKeys() []string {
+	keys := map[string]struct{}{}
+	for sv := range ds.features.spec {
+		keys[sv.Key] = struct{}{}
+	}
+	return slices.Sorted(maps.Keys(keys))
+}
+
+type SpecStats struct {
+	Value      string
+	Kind       SpecKind
+	Number     float64
+	Items      int
+	Returned   int
+	TotalPrice decimal.Decimal
+}
+
+func (s SpecStats) ReturnRate() float64 {
+	return s.ReturnRateEstimate().Rate()
+}
+
+func (s SpecStats) ReturnRateEstimate() RateEstimate {
+	return NewRateEstimate(s.Returned, s.Items)
+}
+
+func (s SpecStats) AveragePrice() decimal.Decimal {
+	if s.Items == 0 {
+		return decimal.Zero
+	}
+	return s.TotalPrice.Div(decimal.NewFromInt(int64(s.Items))).Round(2)
+}
+
+// SpecBreakdown summarizes the recognized items per value of a spec key,
+// optionally within a category. Storage and battery values are sorted by size,
+// the rest by value.
+func (ds *OrderDataset) SpecBreakdown(key string, cat Category) []SpecStats {
+	var res []SpecStats
+	for sv, bitmap := range ds.features.spec {
+		if sv.Key != key {
+			continue
+		}
+		items := roaring.And(bitmap, ds.features.recognizedItem)
+		if cat != "" {
+			inCategory := ds.features.orderItemCategory[cat]
+			if inCategory == nil {
+				continue
+			}
+			items.And(inCategory)
+		}
+		if items.IsEmpty() {
+			continue
+		}
+		stats := SpecStats{Value: sv.Value, Items: int(items.GetCardinality())}
+		stats.Returned = int(roaring.And(items, ds.features.returned).GetCardinality())
+		items.Iterate(func(itemID uint32) bool {
+			item := ds.allItems[itemID]
+			stats.TotalPrice = stats.TotalPrice.Add(item.ItemPrice)
+			if spec, ok := item.Spec(key); ok {
+				stats.Kind, stats.Number = spec.Kind, spec.Number
+			}
+			return true
+		})
+		res = append(res, stats)
+	}
+	slices.SortFunc(res, func(a, b SpecStats) int {
+		return cmp.Or(cmp.Compare(a.Number, b.Number), cmp.Compare(a.Value, b.Value))
+	})
+	return res
+}
@@ -0,0 +1,72 @@
+package reporting_test
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+
+	"refurbed.com/hackathon/reporting"
+)
+
+func TestItemSpecs(t *testing.T) {
+	dataset := importTestDataset(t,
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,iPhone 13,Storage=128 gb|Color=Space Gray|Condition=Excellent|Battery=0.91,400.00,40.00,0,paid,DE,,,Phones>Smartphones",
+		"ORD-2,2025-01-11T10:00:00Z,b@example.com,iPhone 13,storage=1TB|colour=space-grey|grade=B|battery=85%,800.00,40.00,800.00,paid,DE,,,Phones>Smartphones",
+		"ORD-3,2025-01-12T10:00:00Z,c@example.com,iPhone 13,storage=128GB|grade=Grade A|battery=lots,500.00,40.00,500.00,paid,DE,,,Phones>Smartphones",
+		"ORD-4,2025-01-12T10:00:00Z,c@example.com,MacBook Air,storage=256G,900.00,40.00,0,paid,DE,,,Laptops",
+	)
+
+	var first reporting.OrderItem
+	for item := range dataset.AllItems() {
+		first = item
+		break
+	}
+	storage, ok := first.Spec(reporting.SpecKeyStorage)
+	require.True(t, ok)
+	require.Equal(t, reporting.SpecKindStorage, storage.Kind)
+	require.Equal(t, "128GB", storage.Value)
+	require.Equal(t, float64(128<<30), storage.Number)
+	colour, _ := first.Spec(reporting.SpecKeyColour)
+	require.Equal(t, "space grey", colour.Value)
+	grade, _ := first.Spec(reporting.SpecKeyGrade)
+	require.Equal(t, "A", grade.Value)
+	battery, _ := first.Spec(reporting.SpecKeyBattery)
+	require.Equal(t, 91.0, battery.Number)
+
+	require.Equal(t, []string{"battery", "colour", "grade", "storage"}, dataset.AllSpecKeys())
+
+	byGrade := dataset.SpecBreakdown(reporting.SpecKeyGrade, "")
+	require.Len(t, byGrade, 2)
+	require.Equal(t, "A", byGrade[0].Value)
+	require.Equal(t, 2, byGrade[0].Items)
+	require.InDelta(t, 0.5, byGrade[0].ReturnRate(), 1e-9)
+
+	byStorage := dataset.SpecBreakdown(reporting.SpecKeyStorage, "Phones")
+	require.Len(t, byStorage, 2)
+	require.Equal(t, "128GB", byStorage[0].Value)
+	require.True(t, decimal.NewFromInt(450).Equal(byStorage[0].AveragePrice()))
+	require.Equal(t, "1TB", byStorage[1].Value)
+
+	byBattery := dataset.SpecBreakdown(reporting.SpecKeyBattery, "")
+	require.Equal(t, reporting.SpecKindText, byBattery[0].Kind)
+	require.Equal(t, "lots", byBattery[0].Value)
+}
+
+func TestUnparsedAliasedSpecKeepsCanonicalKey(t *testing.T) {
+	dataset := importTestDataset(t,
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,iPhone 13,condition=Like new|Colour=,400.00,40.00,0,paid,DE,,,Phones",
+	)
+
+	var item reporting.OrderItem
+	for item = range dataset.AllItems() {
+		break
+	}
+	grade, ok := item.Spec(reporting.SpecKeyGrade)
+	require.True(t, ok)
+	require.Equal(t, reporting.SpecKindText, grade.Kind)
+	require.Equal(t, "Like new", grade.Value)
+	_, ok = item.Spec(reporting.SpecKeyColour)
+	require.True(t, ok)
+	require.Equal(t, []string{"colour", "grade"}, dataset.AllSpecKeys())
+}
@@ -0,0 +1,107 @@
+package reporting
+
+import "math"
+
+// z95 is the standard normal quantile of a two-sided 95% interval.
+const z95 = 1.959963984540054
+
+// WilsonInterval returns the Wilson score interval of a proportion, which
+// stays within [0, 1] and remains sensible for small samples and rates close
+// to 0 or 1.
+func WilsonInterval(successes, trials int, z float64) (lower, upper float64) {
+	if trials == 0 {
+		return 0, 1
+	}
+	return wilsonInterval(float64(successes)/float64(trials), trials, z)
+}
+
+func wilsonInterval(p float64, trials int, z float64) (lower, upper float64) {
+	n := float64(trials)
+	z2 := z * z
+	center := (p + z2/(2*n)) / (1 + z2/n)
+	spread := z / (1 + z2/n) * math.Sqrt(p*(1-p)/n+z2/(4*n*n))
+	return max(center-spread, 0), min(center+spread, 1)
+}
+
+// RateEstimate is an observed proportion with its 95% Wilson interval.
+type RateEstimate struct {
+	Successes int
+	Trials    int
+	Lower     float64
+	Upper     float64
+	// share is the observed rate of share estimates, whose Successes are
+	// rounded. Zero otherwise, the rate then is Successes / Trials.
+	share float64
+}
+
+func NewRateEstimate(successes, trials int) RateEstimate {
+	lower, upper := WilsonInterval(successes, trials, z95)
+	return RateEstimate{Successes: successes, Trials: trials, Lower: lower, Upper: upper}
+}
+
+// NewShareEstimate treats a share of a total, for example the refunded share
+// of the item value, as a proportion over trials. The interval ignores how
+// unevenly the total is spread over the trials, so it is only a rough guide.
+func NewShareEstimate(share float64, trials int) RateEstimate {
+	if trials == 0 {
+		return NewRateEstimate(0, 0)
+	}
+	share = min(max(share, 0), 1)
+	lower, upper := wilsonInterval(share, trials, z95)
+	return RateEstimate{
+		Successes: int(math.Round(share * float64(trials))),
+		Trials:    trials,
+		Lower:     lower,
+		Upper:     upper,
+		share:     share,
+	}
+}
+
+func (e RateEstimate) Rate() float64 {
+	if e.share != 0 {
+		return e.share
+	}
+	if e.Trials == 0 {
+		return 0
+	}
+	return float64(e.Successes) / float64(e.Trials)
+}
+
+// DefaultMinSampleSize is the number of trials below which a rate is too
+// noisy to act on.
+const DefaultMinSampleSize = 30
+
+func (e RateEstimate) Reliable(minSampleSize int) bool {
+	return e.Trials >= minSampleSize
+}
+
+type ProportionTest struct {
+	A, B RateEstimate
+	// Difference is the rate of A minus the rate of B.
+	Difference float64
+	Z          float64
+	// PValue is two-sided.
+	PValue float64
+}
+
+func (t ProportionTest) Significant(alpha float64) bool {
+	return t.PValue < alpha
+}
+
+// TwoProportionZTest tests whether two rates differ, using the pooled
+// two-proportion z-test. It is only accurate when both samples have a few
+// successes and failures each.
+func TwoProportionZTest(a, b RateEstimate) ProportionTest {
+	t := ProportionTest{A: a, B: b, Difference: a.Rate() - b.Rate(), PValue: 1}
+	if a.Trials == 0 || b.Trials == 0 {
+		return t
+	}
+	pooled := float64(a.Successes+b.Successes) / float64(a.Trials+b.Trials)
+	se := math.Sqrt(pooled * (1 - pooled) * (1/float64(a.Trials) + 1/float64(b.Trials)))
+	if se == 0 {
+		return t
+	}
+	t.Z = t.Difference / se
+	t.PValue = math.Erfc(math.Abs(t.Z) / math.Sqrt2)
+	return t
+}
@@ -0,0 +1,48 @@
+package reporting_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"refurbed.com/hackathon/reporting"
+)
+
+func TestWilsonInterval(t *testing.T) {
+	lower, upper := reporting.WilsonInterval(0, 10, 1.96)
+	require.InDelta(t, 0, lower, 1e-9)
+	require.InDelta(t, 0.2775, upper, 1e-4)
+
+	lower, upper = reporting.WilsonInterval(50, 100, 1.96)
+	require.InDelta(t, 0.4038, lower, 1e-4)
+	require.InDelta(t, 0.5962, upper, 1e-4)
+}
+
+func TestTwoProportionZTest(t *testing.T) {
+	a := reporting.NewRateEstimate(30, 100)
+	b := reporting.NewRateEstimate(15, 100)
+	test := reporting.TwoProportionZTest(a, b)
+	require.InDelta(t, 0.15, test.Difference, 1e-9)
+	require.InDelta(t, 2.5400, test.Z, 1e-4)
+	require.InDelta(t, 0.0111, test.PValue, 1e-4)
+	require.True(t, test.Significant(0.05))
+
+	small := reporting.TwoProportionZTest(reporting.NewRateEstimate(1, 3), reporting.NewRateEstimate(0, 3))
+	require.False(t, small.Significant(0.05))
+	require.False(t, reporting.NewRateEstimate(1, 3).Reliable(reporting.DefaultMinSampleSize))
+}
+
+func TestShareEstimate(t *testing.T) {
+	share := reporting.NewShareEstimate(0.5, 100)
+	require.InDelta(t, 0.5, share.Rate(), 1e-9)
+	require.InDelta(t, 0.4038, share.Lower, 1e-4)
+	require.InDelta(t, 0.5962, share.Upper, 1e-4)
+
+	require.InDelta(t, 1, reporting.NewShareEstimate(1.2, 10).Rate(), 1e-9)
+	require.Zero(t, reporting.NewShareEstimate(0.5, 0).Rate())
+
+	var literal reporting.RateEstimate
+	literal.Successes, literal.Trials = 1, 4
+	require.InDelta(t, 0.25, literal.Rate(), 1e-9)
+	require.InDelta(t, 0.25, reporting.RateEstimate{Successes: 1, Trials: 4}.Rate(), 1e-9)
+}
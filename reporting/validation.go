@@ -0,0 +1,181 @@
+package reporting
+
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+	SeverityInfo
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	default:
+		return "UNKNOWN SEVERITY"
+	}
+}
+
+// ValidationRule checks a single parsed order item. Check returns false when
+// the item violates the rule.
+type ValidationRule struct {
+	Name        string
+	Description string
+	Severity    Severity
+	Check       func(item OrderItem) bool
+}
+
+const maxValidationExamples = 5
+
+type ValidationExample struct {
+	Row      int
+	OrderID  OrderID
+	ItemName string
+}
+
+type RuleResult struct {
+	Rule       ValidationRule
+	Violations int
+	Examples   []ValidationExample
+}
+
+type DataQualityReport struct {
+	CheckedItems int
+	Results      []RuleResult
+}
+
+func (r *DataQualityReport) NumViolations(severity Severity) int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Rule.Severity == severity {
+			n += res.Violations
+		}
+	}
+	return n
+}
+
+func newDataQualityReport(rules []ValidationRule) *DataQualityReport {
+	report := &DataQualityReport{
+		Results: make([]RuleResult, len(rules)),
+	}
+	for i, rule := range rules {
+		report.Results[i].Rule = rule
+	}
+	return report
+}
+
+func (r *DataQualityReport) check(row int, item OrderItem) {
+	r.CheckedItems++
+	for i := range r.Results {
+		res := &r.Results[i]
+		if res.Rule.Check(item) {
+			continue
+		}
+		res.Violations++
+		if len(res.Examples) < maxValidationExamples {
+			res.Examples = append(res.Examples, ValidationExample{
+				Row:      row,
+				OrderID:  item.OrderID,
+				ItemName: item.ItemName,
+			})
+		}
+	}
+}
+
+func DefaultValidationRules() []ValidationRule {
+	return []ValidationRule{
+		{
+			Name:        "delivered_before_ordered",
+			Description: "delivered_at is before ordered_at",
+			Severity:    SeverityError,
+			Check: func(item OrderItem) bool {
+				return item.DeliveredAt.IsZero() || !item.DeliveredAt.Before(item.OrderedAt)
+			},
+		},
+		{
+			Name:        "shipped_before_ordered",
+			Description: "shipped_at is before ordered_at",
+			Severity:    SeverityError,
+			Check: func(item OrderItem) bool {
+				return item.ShippedAt.IsZero() || !item.ShippedAt.Before(item.OrderedAt)
+			},
+		},
+		{
+			Name:        "delivered_before_shipped",
+			Description: "delivered_at is before shipped_at",
+			Severity:    SeverityWarning,
+			Check: func(item OrderItem) bool {
+				return item.ShippedAt.IsZero() || item.DeliveredAt.IsZero() || !item.DeliveredAt.Before(item.ShippedAt)
+			},
+		},
+		{
+			Name:        "refund_exceeds_price",
+			Description: "refunded is greater than item_price",
+			Severity:    SeverityError,
+			Check: func(item OrderItem) bool {
+				return item.Refunded.LessThanOrEqual(item.ItemPrice)
+			},
+		},
+		{
+			Name:        "negative_price",
+			Description: "item_price is negative",
+			Severity:    SeverityError,
+			Check: func(item OrderItem) bool {
+				return !item.ItemPrice.IsNegative()
+			},
+		},
+		{
+			Name:        "negative_refund",
+			Description: "refunded is negative",
+			Severity:    SeverityError,
+			Check: func(item OrderItem) bool {
+				return !item.Refunded.IsNegative()
+			},
+		},
+		{
+			Name:        "negative_commission",
+			Description: "commission is negative",
+			Severity:    SeverityWarning,
+			Check: func(item OrderItem) bool {
+				return !item.Commission.IsNegative()
+			},
+		},
+		{
+			Name:        "empty_category",
+			Description: "category is empty",
+			Severity:    SeverityWarning,
+			Check: func(item OrderItem) bool {
+				return len(item.Category) > 0
+			},
+		},
+		{
+			Name:        "unknown_payment_status",
+			Description: "payment_status is not a known status",
+			Severity:    SeverityWarning,
+			Check: func(item OrderItem) bool {
+				return item.PaymentStatus != PaymentStatusUnknown
+			},
+		},
+		{
+			Name:        "missing_customer_email",
+			Description: "customer_email is empty",
+			Severity:    SeverityInfo,
+			Check: func(item OrderItem) bool {
+				return item.CustomerEmail != ""
+			},
+		},
+		{
+			Name:        "not_delivered",
+			Description: "delivered_at is empty",
+			Severity:    SeverityInfo,
+			Check: func(item OrderItem) bool {
+				return !item.DeliveredAt.IsZero()
+			},
+		},
+	}
+}
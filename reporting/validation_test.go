@@ -0,0 +1,58 @@
+package reporting_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"refurbed.com/hackathon/reporting"
+)
+
+func TestDataQualityReport(t *testing.T) {
+	dataset := importTestDataset(t,
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,iPhone 13,storage=128GB,500.00,50.00,0,paid,DE,2025-01-11T10:00:00Z,2025-01-13T10:00:00Z,Phones>Smartphones",
+		"ORD-2,2025-01-10T10:00:00Z,b@example.com,iPhone 12,storage=64GB,300.00,-5.00,400.00,paid,AT,2025-01-11T10:00:00Z,2025-01-09T10:00:00Z,Phones>Smartphones",
+		"ORD-3,2025-01-10T10:00:00Z,c@example.com,Unknown,,100.00,10.00,0,weird,AT,,,",
+	)
+
+	report := dataset.DataQuality()
+	require.Equal(t, 3, report.CheckedItems)
+
+	violations := map[string]reporting.RuleResult{}
+	for _, res := range report.Results {
+		violations[res.Rule.Name] = res
+	}
+	require.Equal(t, 1, violations["delivered_before_ordered"].Violations)
+	require.Equal(t, 1, violations["refund_exceeds_price"].Violations)
+	require.Equal(t, 1, violations["negative_commission"].Violations)
+	require.Equal(t, 1, violations["empty_category"].Violations)
+	require.Equal(t, 1, violations["unknown_payment_status"].Violations)
+	require.Equal(t, 0, violations["negative_price"].Violations)
+
+	examples := violations["refund_exceeds_price"].Examples
+	require.Len(t, examples, 1)
+	require.Equal(t, reporting.OrderID("ORD-2"), examples[0].OrderID)
+	require.Equal(t, 3, examples[0].Row)
+
+	require.Equal(t, 2, report.NumViolations(reporting.SeverityError))
+}
+
+func TestImportWithCustomValidationRules(t *testing.T) {
+	rule := reporting.ValidationRule{
+		Name:     "austria_only",
+		Severity: reporting.SeverityInfo,
+		Check: func(item reporting.OrderItem) bool {
+			return item.Country == "AT"
+		},
+	}
+	in := testCSVHeader + "\n" +
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,iPhone 13,,500.00,50.00,0,paid,DE,,,Phones\n"
+
+	dataset, err := reporting.ImportOrderDatasetFromCSV(strings.NewReader(in), reporting.WithValidationRules(rule))
+	require.NoError(t, err)
+
+	report := dataset.DataQuality()
+	require.Len(t, report.Results, 1)
+	require.Equal(t, 1, report.Results[0].Violations)
+}
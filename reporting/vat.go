@@ -0,0 +1,193 @@
+package reporting
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type RevenueBasis int
+
+const (
+	RevenueBasisGross RevenueBasis = iota
+	RevenueBasisNet
+)
+
+func (b RevenueBasis) String() string {
+	switch b {
+	case RevenueBasisGross:
+		return "gross"
+	case RevenueBasisNet:
+		return "net"
+	default:
+		return "UNKNOWN BASIS"
+	}
+}
+
+type vatRate struct {
+	EffectiveFrom time.Time
+	Rate          decimal.Decimal
+}
+
+// VATRates is a date-effective VAT rate table per country. Rates are stored as
+// fractions, so a 19% rate is 0.19.
+type VATRates struct {
+	rates map[string][]vatRate
+}
+
+// LoadVATRatesFromCSV reads a table with the columns country, effective_from
+// and rate, where rate is a percentage.
+func LoadVATRatesFromCSV(r io.Reader) (*VATRates, error) {
+	csvr := csv.NewReader(r)
+
+	headerFields, err := csvr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unexpected I/O error before CSV header: %w", err)
+	}
+	countryIdx := slices.Index(headerFields, "country")
+	effectiveFromIdx := slices.Index(headerFields, "effective_from")
+	rateIdx := slices.Index(headerFields, "rate")
+	if countryIdx == -1 || effectiveFromIdx == -1 || rateIdx == -1 {
+		return nil, fmt.Errorf("VAT rates header %q must contain country, effective_from and rate", headerFields)
+	}
+
+	vat := &VATRates{rates: map[string][]vatRate{}}
+	for {
+		fields, err := csvr.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("read CSV row: %w", err)
+		}
+		effectiveFrom, err := time.Parse(time.DateOnly, fields[effectiveFromIdx])
+		if err != nil {
+			return nil, fmt.Errorf("parse VAT effective_from: %w", err)
+		}
+		percent, err := decimal.NewFromString(fields[rateIdx])
+		if err != nil {
+			return nil, fmt.Errorf("parse VAT rate: %w", err)
+		}
+		if percent.IsNegative() {
+			return nil, fmt.Errorf("VAT rate for %s on %s must not be negative", fields[countryIdx], fields[effectiveFromIdx])
+		}
+		vat.Add(strings.ToUpper(fields[countryIdx]), effectiveFrom, percent.Div(decimal.NewFromInt(100)))
+	}
+	return vat, nil
+}
+
+func (vat *VATRates) Add(country string, effectiveFrom time.Time, rate decimal.Decimal) {
+	if vat.rates == nil {
+		vat.rates = map[string][]vatRate{}
+	}
+	rates := append(vat.rates[country], vatRate{EffectiveFrom: effectiveFrom, Rate: rate})
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].EffectiveFrom.Before(rates[j].EffectiveFrom)
+	})
+	vat.rates[country] = rates
+}
+
+func (vat *VATRates) RateAt(country string, at time.Time) (decimal.Decimal, error) {
+	rates := vat.rates[strings.ToUpper(country)]
+	idx := sort.Search(len(rates), func(i int) bool {
+		return rates[i].EffectiveFrom.After(at)
+	})
+	if idx == 0 {
+		return decimal.Zero, fmt.Errorf("no VAT rate for %q effective at %s", country, at.Format(time.DateOnly))
+	}
+	return rates[idx-1].Rate, nil
+}
+
+// applyVATRate sets the VAT rate of the item's country on its order date.
+// Items without a rate keep a rate of 0, the missing_vat_rate validation rule
+// reports them.
+func applyVATRate(item OrderItem, vat *VATRates) OrderItem {
+	if vat == nil {
+		return item
+	}
+	if rate, err := vat.RateAt(item.Country, item.OrderedAt); err == nil {
+		item.VATRate = rate
+	}
+	return item
+}
+
+// missingVATRateRule flags items whose country has no VAT rate on the order
+// date, so their net amounts equal the gross amounts.
+func missingVATRateRule(vat *VATRates) ValidationRule {
+	return ValidationRule{
+		Name:        "missing_vat_rate",
+		Description: "no VAT rate for country on ordered_at, net amounts include VAT",
+		Severity:    SeverityWarning,
+		Check: func(item OrderItem) bool {
+			_, err := vat.RateAt(item.Country, item.OrderedAt)
+			return err == nil
+		},
+	}
+}
+
+func excludingVAT(gross, rate decimal.Decimal) decimal.Decimal {
+	if rate.IsZero() {
+		return gross
+	}
+	return gross.Div(decimal.NewFromInt(1).Add(rate)).Round(2)
+}
+
+// Revenue is the gross amount kept from the item, item_price minus refunded.
+func (r OrderItem) Revenue() decimal.Decimal {
+	return r.ItemPrice.Sub(r.Refunded)
+}
+
+func (r OrderItem) NetPrice() decimal.Decimal {
+	return excludingVAT(r.ItemPrice, r.VATRate)
+}
+
+func (r OrderItem) NetRevenue() decimal.Decimal {
+	return excludingVAT(r.Revenue(), r.VATRate)
+}
+
+func (r OrderItem) VATAmount() decimal.Decimal {
+	return r.Revenue().Sub(r.NetRevenue())
+}
+
+func (r OrderItem) price(basis RevenueBasis) decimal.Decimal {
+	if basis == RevenueBasisNet {
+		return r.NetPrice()
+	}
+	return r.ItemPrice
+}
+
+func (r OrderItem) revenue(basis RevenueBasis) decimal.Decimal {
+	if basis == RevenueBasisNet {
+		return r.NetRevenue()
+	}
+	return r.Revenue()
+}
+
+func (ds *OrderDataset) HasVATRates() bool {
+	return ds.hasVATRates
+}
+
+func (ds *OrderDataset) TotalRevenueFor(basis RevenueBasis) decimal.Decimal {
+	if basis == RevenueBasisNet {
+		return ds.totalNetRevenue
+	}
+	return ds.totalRevenue
+}
+
+func (ds *OrderDataset) AOVFor(basis RevenueBasis) decimal.Decimal {
+	if basis == RevenueBasisNet {
+		return ds.perRecognizedOrder(ds.totalNet)
+	}
+	return ds.perRecognizedOrder(ds.totalGross)
+}
+
+func (ds *OrderDataset) TotalVAT() decimal.Decimal {
+	return ds.totalRevenue.Sub(ds.totalNetRevenue)
+}
@@ -0,0 +1,64 @@
+package reporting_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+
+	"refurbed.com/hackathon/reporting"
+)
+
+const testVATRates = `country,effective_from,rate
+DE,2020-01-01,19
+AT,2020-01-01,20
+AT,2025-01-15,25
+`
+
+func TestNetRevenue(t *testing.T) {
+	vat, err := reporting.LoadVATRatesFromCSV(strings.NewReader(testVATRates))
+	require.NoError(t, err)
+
+	in := testCSVHeader + "\n" +
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,iPhone 13,,119.00,10.00,0,paid,DE,,,Phones\n" +
+		"ORD-2,2025-01-10T10:00:00Z,b@example.com,iPhone 12,,120.00,10.00,60.00,paid,AT,,,Phones\n" +
+		"ORD-3,2025-01-20T10:00:00Z,c@example.com,iPhone 11,,125.00,10.00,0,paid,AT,,,Phones\n"
+
+	dataset, err := reporting.ImportOrderDatasetFromCSV(strings.NewReader(in), reporting.WithVATRates(vat))
+	require.NoError(t, err)
+	require.True(t, dataset.HasVATRates())
+
+	require.True(t, decimal.NewFromInt(304).Equal(dataset.TotalRevenueFor(reporting.RevenueBasisGross)))
+	require.True(t, decimal.NewFromInt(250).Equal(dataset.TotalRevenueFor(reporting.RevenueBasisNet)), dataset.TotalRevenueFor(reporting.RevenueBasisNet).String())
+	require.True(t, decimal.NewFromInt(54).Equal(dataset.TotalVAT()))
+	require.True(t, decimal.NewFromInt(100).Equal(dataset.AOVFor(reporting.RevenueBasisNet)))
+
+	day := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	byDay := dataset.RevenueByDayFor(reporting.RevenueBasisNet, day, day)
+	require.Len(t, byDay, 1)
+	require.True(t, decimal.NewFromInt(150).Equal(byDay[0].Revenue))
+}
+
+func TestMissingVATRate(t *testing.T) {
+	vat, err := reporting.LoadVATRatesFromCSV(strings.NewReader(testVATRates))
+	require.NoError(t, err)
+
+	in := testCSVHeader + "\n" +
+		"ORD-1,2025-01-10T10:00:00Z,a@example.com,iPhone 13,,119.00,10.00,0,paid,DE,,,Phones\n" +
+		"ORD-2,2025-01-10T10:00:00Z,b@example.com,iPhone 12,,100.00,10.00,0,paid,FR,,,Phones\n"
+
+	dataset, err := reporting.ImportOrderDatasetFromCSV(strings.NewReader(in), reporting.WithVATRates(vat))
+	require.NoError(t, err)
+	require.True(t, decimal.NewFromInt(200).Equal(dataset.TotalRevenueFor(reporting.RevenueBasisNet)))
+
+	var missing reporting.RuleResult
+	for _, res := range dataset.DataQuality().Results {
+		if res.Rule.Name == "missing_vat_rate" {
+			missing = res
+		}
+	}
+	require.Equal(t, 1, missing.Violations)
+	require.Equal(t, reporting.OrderID("ORD-2"), missing.Examples[0].OrderID)
+}